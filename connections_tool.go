@@ -0,0 +1,266 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+// connection types offered by the dashboard mapped to their connection type refs
+var connectionTypeRefs = map[string]string{
+	"s3":  "s3",
+	"uri": "uri-v1",
+	"oci": "oci-v1",
+}
+
+// Lists connections in a project, only key names are returned and never the secret values
+func ListConnections(ctx context.Context, req *mcp.CallToolRequest, input ListWorkbenchesInput) (*mcp.CallToolResult, ListConnectionsOutput, error) {
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, ListConnectionsOutput{}, err
+	}
+
+	secrets, err := dyn.Resource(secretsGVR).Namespace(input.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "opendatahub.io/dashboard=true",
+	})
+	if err != nil {
+		return nil, ListConnectionsOutput{}, fmt.Errorf("failed to list connections: %v", err)
+	}
+
+	msg := ""
+	for _, secret := range secrets.Items {
+		connectionType := connectionType(secret)
+		if connectionType == "" {
+			continue
+		}
+		msg += fmt.Sprintf("- %s", secret.GetName())
+		if displayName := secret.GetAnnotations()["openshift.io/display-name"]; displayName != "" && displayName != secret.GetName() {
+			msg += fmt.Sprintf(" (%s)", displayName)
+		}
+		msg += fmt.Sprintf(": type %s", connectionType)
+
+		data, _, _ := unstructured.NestedMap(secret.Object, "data")
+		keys := make([]string, 0, len(data))
+		for key := range data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		if len(keys) > 0 {
+			msg += fmt.Sprintf(", keys %s", strings.Join(keys, ", "))
+		}
+		if description := secret.GetAnnotations()["openshift.io/description"]; description != "" {
+			msg += fmt.Sprintf(" - %s", description)
+		}
+		msg += "\n"
+	}
+	return nil, ListConnectionsOutput{Connections: msg}, nil
+}
+
+func CreateConnection(ctx context.Context, req *mcp.CallToolRequest, input CreateConnectionInput) (*mcp.CallToolResult, ConnectionOutput, error) {
+	typeRef, ok := connectionTypeRefs[input.ConnectionType]
+	if !ok {
+		return nil, ConnectionOutput{}, fmt.Errorf("connection type must be s3, uri or oci, got: %s", input.ConnectionType)
+	}
+
+	secretType := "Opaque"
+	var stringData map[string]interface{}
+	switch input.ConnectionType {
+	case "s3":
+		if input.AccessKeyID == "" || input.SecretAccessKey == "" || input.Endpoint == "" || input.Bucket == "" {
+			return nil, ConnectionOutput{}, fmt.Errorf("s3 connection requires access key id, secret access key, endpoint and bucket")
+		}
+		region := input.Region
+		if region == "" {
+			region = "us-east-1"
+		}
+		stringData = map[string]interface{}{
+			"AWS_ACCESS_KEY_ID":     input.AccessKeyID,
+			"AWS_SECRET_ACCESS_KEY": input.SecretAccessKey,
+			"AWS_S3_ENDPOINT":       input.Endpoint,
+			"AWS_S3_BUCKET":         input.Bucket,
+			"AWS_DEFAULT_REGION":    region,
+		}
+	case "uri":
+		if input.URI == "" {
+			return nil, ConnectionOutput{}, fmt.Errorf("uri connection requires uri")
+		}
+		stringData = map[string]interface{}{
+			"URI": input.URI,
+		}
+	case "oci":
+		if input.Registry == "" {
+			return nil, ConnectionOutput{}, fmt.Errorf("oci connection requires registry")
+		}
+		auths := map[string]interface{}{}
+		if input.Username != "" || input.Password != "" {
+			auths[input.Registry] = map[string]string{
+				"auth": base64.StdEncoding.EncodeToString([]byte(input.Username + ":" + input.Password)),
+			}
+		}
+		dockerConfig, err := json.Marshal(map[string]interface{}{"auths": auths})
+		if err != nil {
+			return nil, ConnectionOutput{}, fmt.Errorf("failed to marshal docker config: %v", err)
+		}
+		secretType = "kubernetes.io/dockerconfigjson"
+		stringData = map[string]interface{}{
+			".dockerconfigjson": string(dockerConfig),
+			"OCI_HOST":          input.Registry,
+		}
+	}
+
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, ConnectionOutput{}, err
+	}
+
+	displayName := input.DisplayName
+	if displayName == "" {
+		displayName = input.ConnectionName
+	}
+	annotations := map[string]interface{}{
+		"openshift.io/display-name":          displayName,
+		"openshift.io/description":           input.Description,
+		"opendatahub.io/connection-type-ref": typeRef,
+	}
+	// older components such as pipelines and model serving look for the legacy annotation
+	if input.ConnectionType == "s3" {
+		annotations["opendatahub.io/connection-type"] = "s3"
+	}
+
+	secret := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"type":       secretType,
+			"metadata": map[string]interface{}{
+				"name":      input.ConnectionName,
+				"namespace": input.Namespace,
+				"labels": map[string]interface{}{
+					"opendatahub.io/dashboard": "true",
+				},
+				"annotations": annotations,
+			},
+			"stringData": stringData,
+		},
+	}
+
+	_, err = dyn.Resource(secretsGVR).Namespace(input.Namespace).Create(ctx, secret, metav1.CreateOptions{})
+	if err != nil {
+		return nil, ConnectionOutput{}, fmt.Errorf("failed to create connection %s: %v", input.ConnectionName, err)
+	}
+
+	return nil, ConnectionOutput{Message: fmt.Sprintf("Connection %s of type %s was succesfully created!", input.ConnectionName, input.ConnectionType)}, nil
+}
+
+func DeleteConnection(ctx context.Context, req *mcp.CallToolRequest, input ConnectionInput) (*mcp.CallToolResult, ConnectionOutput, error) {
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, ConnectionOutput{}, err
+	}
+
+	secret, err := getConnection(ctx, dyn, input.Namespace, input.ConnectionName)
+	if err != nil {
+		return nil, ConnectionOutput{}, err
+	}
+
+	err = dyn.Resource(secretsGVR).Namespace(input.Namespace).Delete(ctx, secret.GetName(), metav1.DeleteOptions{})
+	if err != nil {
+		return nil, ConnectionOutput{}, fmt.Errorf("failed to delete connection %s: %v", input.ConnectionName, err)
+	}
+
+	return nil, ConnectionOutput{Message: fmt.Sprintf("Connection %s was deleted", input.ConnectionName)}, nil
+}
+
+// Checks the credentials of an S3 connection by listing its bucket on the configured endpoint
+func CheckConnection(ctx context.Context, req *mcp.CallToolRequest, input ConnectionInput) (*mcp.CallToolResult, TestConnectionOutput, error) {
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, TestConnectionOutput{}, err
+	}
+
+	secret, err := getConnection(ctx, dyn, input.Namespace, input.ConnectionName)
+	if err != nil {
+		return nil, TestConnectionOutput{}, err
+	}
+	if connectionType(*secret) != "s3" {
+		return nil, TestConnectionOutput{}, fmt.Errorf("connection %s is of type %s, only s3 connections can be tested", input.ConnectionName, connectionType(*secret))
+	}
+
+	values, err := connectionValues(*secret)
+	if err != nil {
+		return nil, TestConnectionOutput{}, err
+	}
+	for _, key := range []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_S3_ENDPOINT", "AWS_S3_BUCKET"} {
+		if values[key] == "" {
+			return nil, TestConnectionOutput{Reason: "InvalidConnection", Message: fmt.Sprintf("connection %s is missing %s", input.ConnectionName, key)}, nil
+		}
+	}
+
+	client := newS3Client(values["AWS_S3_ENDPOINT"], values["AWS_DEFAULT_REGION"], values["AWS_ACCESS_KEY_ID"], values["AWS_SECRET_ACCESS_KEY"])
+	bucket := values["AWS_S3_BUCKET"]
+	objects, err := client.listObjects(ctx, bucket, "", 10)
+	if err != nil {
+		s3Err, ok := err.(*s3Error)
+		if !ok {
+			return nil, TestConnectionOutput{Reason: "EndpointUnreachable", Message: fmt.Sprintf("failed to reach %s: %v", values["AWS_S3_ENDPOINT"], err)}, nil
+		}
+		switch s3Err.Code {
+		case "InvalidAccessKeyId", "SignatureDoesNotMatch", "InvalidToken", "ExpiredToken":
+			return nil, TestConnectionOutput{Reason: "InvalidCredentials", Message: fmt.Sprintf("the endpoint rejected the credentials: %v", s3Err)}, nil
+		case "AccessDenied":
+			return nil, TestConnectionOutput{Reason: "AccessDenied", Message: fmt.Sprintf("the credentials are not allowed to list bucket %s: %v", bucket, s3Err)}, nil
+		case "NoSuchBucket":
+			return nil, TestConnectionOutput{Reason: "BucketNotFound", Message: fmt.Sprintf("bucket %s does not exist", bucket)}, nil
+		}
+		return nil, TestConnectionOutput{Reason: "Unknown", Message: fmt.Sprintf("failed to list bucket %s: %v", bucket, s3Err)}, nil
+	}
+
+	return nil, TestConnectionOutput{
+		Success: true,
+		Reason:  "Ok",
+		Message: fmt.Sprintf("Credentials are valid and bucket %s was listed (%d objects shown)", bucket, len(objects)),
+		Objects: objects,
+	}, nil
+}
+
+// getConnection returns the secret backing the connection and fails for secrets which are not connections
+func getConnection(ctx context.Context, dyn dynamic.Interface, namespace, name string) (*unstructured.Unstructured, error) {
+	secret, err := dyn.Resource(secretsGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection %s: %v", name, err)
+	}
+	if secret.GetLabels()["opendatahub.io/dashboard"] != "true" || connectionType(*secret) == "" {
+		return nil, fmt.Errorf("secret %s is not a connection", name)
+	}
+	return secret, nil
+}
+
+func connectionType(secret unstructured.Unstructured) string {
+	annotations := secret.GetAnnotations()
+	if typeRef := annotations["opendatahub.io/connection-type-ref"]; typeRef != "" {
+		return typeRef
+	}
+	return annotations["opendatahub.io/connection-type"]
+}
+
+// connectionValues decodes the data of a connection secret, only for use inside the server
+func connectionValues(secret unstructured.Unstructured) (map[string]string, error) {
+	data, _, _ := unstructured.NestedStringMap(secret.Object, "data")
+	values := map[string]string{}
+	for key, encoded := range data {
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s of connection %s: %v", key, secret.GetName(), err)
+		}
+		values[key] = string(decoded)
+	}
+	return values, nil
+}
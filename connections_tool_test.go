@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func newUnstructuredConnection(name, namespace, typeRef string, data map[string]string) *unstructured.Unstructured {
+	encoded := map[string]interface{}{}
+	for key, value := range data {
+		encoded[key] = base64.StdEncoding.EncodeToString([]byte(value))
+	}
+	u := &unstructured.Unstructured{Object: map[string]interface{}{"data": encoded}}
+	u.SetGroupVersionKind(secretsGVR.GroupVersion().WithKind("Secret"))
+	u.SetName(name)
+	u.SetNamespace(namespace)
+	u.SetLabels(map[string]string{"opendatahub.io/dashboard": "true"})
+	u.SetAnnotations(map[string]string{"opendatahub.io/connection-type-ref": typeRef})
+	return u
+}
+
+func TestConnections(t *testing.T) {
+	orig := getDynamicClient
+	defer func() { getDynamicClient = orig }()
+
+	plainSecret := &unstructured.Unstructured{}
+	plainSecret.SetGroupVersionKind(secretsGVR.GroupVersion().WithKind("Secret"))
+	plainSecret.SetName("plain")
+	plainSecret.SetNamespace("ns1")
+	plainSecret.SetLabels(map[string]string{"opendatahub.io/dashboard": "true"})
+
+	scheme := runtime.NewScheme()
+	client := dynamicfake.NewSimpleDynamicClient(scheme,
+		newUnstructuredConnection("models", "ns1", "s3", map[string]string{
+			"AWS_ACCESS_KEY_ID":     "minio",
+			"AWS_SECRET_ACCESS_KEY": "supersecret",
+		}),
+		plainSecret,
+	)
+	getDynamicClient = func() (dynamic.Interface, error) {
+		return client, nil
+	}
+	ctx := context.Background()
+
+	_, list, err := ListConnections(ctx, nil, ListWorkbenchesInput{Namespace: "ns1"})
+	if err != nil {
+		t.Fatalf("ListConnections returned error: %v", err)
+	}
+	if !strings.Contains(list.Connections, "- models: type s3, keys AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY\n") {
+		t.Errorf("expected models connection in output, got: %q", list.Connections)
+	}
+	if strings.Contains(list.Connections, "supersecret") || strings.Contains(list.Connections, "plain") {
+		t.Errorf("did not expect secret values or plain secrets in output, got: %q", list.Connections)
+	}
+
+	if _, _, err := CreateConnection(ctx, nil, CreateConnectionInput{Namespace: "ns1", ConnectionName: "bad", ConnectionType: "s3"}); err == nil {
+		t.Errorf("expected error for s3 connection without credentials")
+	}
+
+	_, out, err := CreateConnection(ctx, nil, CreateConnectionInput{Namespace: "ns1", ConnectionName: "registry", ConnectionType: "oci", Registry: "quay.io", Username: "bot", Password: "token"})
+	if err != nil {
+		t.Fatalf("CreateConnection returned error: %v", err)
+	}
+	if out.Message != "Connection registry of type oci was succesfully created!" {
+		t.Errorf("unexpected message: %q", out.Message)
+	}
+	created, err := client.Resource(secretsGVR).Namespace("ns1").Get(ctx, "registry", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected connection secret to be created: %v", err)
+	}
+	if created.GetAnnotations()["opendatahub.io/connection-type-ref"] != "oci-v1" {
+		t.Errorf("expected oci-v1 connection type, got: %v", created.GetAnnotations())
+	}
+
+	if _, _, err := DeleteConnection(ctx, nil, ConnectionInput{Namespace: "ns1", ConnectionName: "plain"}); err == nil {
+		t.Errorf("expected error when deleting a secret which is not a connection")
+	}
+	_, out, err = DeleteConnection(ctx, nil, ConnectionInput{Namespace: "ns1", ConnectionName: "models"})
+	if err != nil {
+		t.Fatalf("DeleteConnection returned error: %v", err)
+	}
+	if out.Message != "Connection models was deleted" {
+		t.Errorf("unexpected message: %q", out.Message)
+	}
+}
+
+// newFakeS3Server stands in for MinIO, it accepts requests signed with the minio/minio123 key pair
+// and serves a single bucket named models
+func newFakeS3Server(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signedAt, err := time.Parse("20060102T150405Z", r.Header.Get("X-Amz-Date"))
+		if err != nil {
+			t.Errorf("missing or invalid X-Amz-Date header: %v", err)
+		}
+		expected := r.Clone(r.Context())
+		expected.URL.Host = r.Host
+		newS3Client("unused", "us-east-1", "minio", "minio123").sign(expected, signedAt)
+		if r.Header.Get("Authorization") != expected.Header.Get("Authorization") {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`<Error><Code>SignatureDoesNotMatch</Code><Message>signature mismatch</Message></Error>`))
+			return
+		}
+		if r.URL.Path != "/models" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`<Error><Code>NoSuchBucket</Code><Message>bucket missing</Message></Error>`))
+			return
+		}
+		if r.URL.Query().Get("list-type") != "2" {
+			t.Errorf("expected ListObjectsV2 request, got query: %s", r.URL.RawQuery)
+		}
+		_, _ = w.Write([]byte(`<ListBucketResult><KeyCount>1</KeyCount><Contents><Key>granite/model.safetensors</Key><Size>42</Size></Contents></ListBucketResult>`))
+	}))
+}
+
+func TestCheckConnection(t *testing.T) {
+	orig := getDynamicClient
+	defer func() { getDynamicClient = orig }()
+
+	server := newFakeS3Server(t)
+	defer server.Close()
+
+	s3Connection := func(name, secretKey, bucket string) *unstructured.Unstructured {
+		return newUnstructuredConnection(name, "ns1", "s3", map[string]string{
+			"AWS_ACCESS_KEY_ID":     "minio",
+			"AWS_SECRET_ACCESS_KEY": secretKey,
+			"AWS_S3_ENDPOINT":       server.URL,
+			"AWS_S3_BUCKET":         bucket,
+		})
+	}
+	scheme := runtime.NewScheme()
+	client := dynamicfake.NewSimpleDynamicClient(scheme,
+		s3Connection("valid", "minio123", "models"),
+		s3Connection("wrong-key", "nope", "models"),
+		s3Connection("no-bucket", "minio123", "datasets"),
+		newUnstructuredConnection("uri", "ns1", "uri-v1", map[string]string{"URI": "https://example.com"}),
+	)
+	getDynamicClient = func() (dynamic.Interface, error) {
+		return client, nil
+	}
+	ctx := context.Background()
+
+	_, out, err := CheckConnection(ctx, nil, ConnectionInput{Namespace: "ns1", ConnectionName: "valid"})
+	if err != nil {
+		t.Fatalf("CheckConnection returned error: %v", err)
+	}
+	if !out.Success || out.Reason != "Ok" || len(out.Objects) != 1 || out.Objects[0] != "granite/model.safetensors" {
+		t.Errorf("expected successful listing, got: %+v", out)
+	}
+
+	_, out, err = CheckConnection(ctx, nil, ConnectionInput{Namespace: "ns1", ConnectionName: "wrong-key"})
+	if err != nil {
+		t.Fatalf("CheckConnection returned error: %v", err)
+	}
+	if out.Success || out.Reason != "InvalidCredentials" {
+		t.Errorf("expected InvalidCredentials, got: %+v", out)
+	}
+	if strings.Contains(out.Message, "nope") {
+		t.Errorf("did not expect the secret key in the message, got: %q", out.Message)
+	}
+
+	_, out, err = CheckConnection(ctx, nil, ConnectionInput{Namespace: "ns1", ConnectionName: "no-bucket"})
+	if err != nil {
+		t.Fatalf("CheckConnection returned error: %v", err)
+	}
+	if out.Success || out.Reason != "BucketNotFound" {
+		t.Errorf("expected BucketNotFound, got: %+v", out)
+	}
+
+	if _, _, err := CheckConnection(ctx, nil, ConnectionInput{Namespace: "ns1", ConnectionName: "uri"}); err == nil {
+		t.Errorf("expected error when testing a uri connection")
+	}
+}
@@ -17,14 +17,83 @@ func CreateWorkbench(ctx context.Context, req *mcp.CallToolRequest, input Create
 		return nil, WorkbenchOutput{}, err
 	}
 
-	repoURL, gitCommit, imageName, err := GetImageInfo(ctx, input.ImageDisplayName, input.ImageTag)
+	// an empty tag resolves to the recommended one, which is reported back
+	repoURL, imageTag, imageName, err := GetImageInfo(ctx, input.ImageDisplayName, input.ImageTag)
 	if err != nil {
 		return nil, WorkbenchOutput{}, fmt.Errorf("failed to lookup image info: %v", err)
 	}
 
-	err = createPersistentVolumeClaim(ctx, dyn, input.Namespace, input.WorkbenchName, "10Gi")
+	extraEnv, err := workbenchEnvVars(input.EnvVars, input.SecretEnvVars)
 	if err != nil {
-		return nil, WorkbenchOutput{}, fmt.Errorf("failed to create PVC: %v", err)
+		return nil, WorkbenchOutput{}, err
+	}
+	if err := checkConnectionsExist(ctx, dyn, input.Namespace, input.Connections); err != nil {
+		return nil, WorkbenchOutput{}, err
+	}
+
+	// existing storage and its mount paths are checked before anything gets created
+	claims := map[string]bool{input.StorageName: true}
+	if input.StorageName == "" {
+		claims = map[string]bool{input.WorkbenchName: true}
+	}
+	mounts := []interface{}{
+		map[string]interface{}{"mountPath": workbenchHomeDir},
+		map[string]interface{}{"mountPath": "/dev/shm"},
+	}
+	for _, storage := range input.AdditionalStorage {
+		if claims[storage.StorageName] {
+			return nil, WorkbenchOutput{}, fmt.Errorf("storage %s is listed more than once", storage.StorageName)
+		}
+		claims[storage.StorageName] = true
+		mount := storageVolumeMount("", storage.StorageName, storage.MountPath)
+		if err := checkMountPath(mounts, mount["mountPath"].(string), input.WorkbenchName); err != nil {
+			return nil, WorkbenchOutput{}, err
+		}
+		mounts = append(mounts, mount)
+		if err := checkStorageAvailable(ctx, dyn, input.Namespace, storage.StorageName, input.WorkbenchName); err != nil {
+			return nil, WorkbenchOutput{}, err
+		}
+	}
+
+	homeClaim := input.StorageName
+	if homeClaim == "" {
+		homeClaim = input.WorkbenchName
+		err = createPersistentVolumeClaim(ctx, dyn, input.Namespace, input.WorkbenchName, "10Gi")
+		if err != nil {
+			return nil, WorkbenchOutput{}, fmt.Errorf("failed to create PVC: %v", err)
+		}
+	} else if err := checkStorageAvailable(ctx, dyn, input.Namespace, homeClaim, input.WorkbenchName); err != nil {
+		return nil, WorkbenchOutput{}, err
+	}
+
+	volumeMounts := []interface{}{
+		map[string]interface{}{
+			"mountPath": "/opt/app-root/src/",
+			"name":      "storage-volume",
+		},
+		map[string]interface{}{
+			"mountPath": "/dev/shm",
+			"name":      "shm",
+		},
+	}
+	volumes := []interface{}{
+		map[string]interface{}{
+			"name": "storage-volume",
+			"persistentVolumeClaim": map[string]interface{}{
+				"claimName": homeClaim,
+			},
+		},
+		map[string]interface{}{
+			"name": "shm",
+			"emptyDir": map[string]interface{}{
+				"medium": "Memory",
+			},
+		},
+	}
+	for _, storage := range input.AdditionalStorage {
+		volumeName := storageVolumeName(volumes)
+		volumeMounts = append(volumeMounts, storageVolumeMount(volumeName, storage.StorageName, storage.MountPath))
+		volumes = append(volumes, storageVolume(volumeName, storage.StorageName))
 	}
 
 	notebookArgs := fmt.Sprintf(`--ServerApp.port=8888
@@ -33,9 +102,37 @@ func CreateWorkbench(ctx context.Context, req *mcp.CallToolRequest, input Create
                   --ServerApp.base_url=/notebook/%s/%s
                   --ServerApp.quit_button=False`, input.Namespace, input.WorkbenchName)
 
-	imageFull := repoURL
-	if input.ImageTag != "" {
-		imageFull = fmt.Sprintf("%s:%s", repoURL, input.ImageTag)
+	imageFull := fmt.Sprintf("%s:%s", repoURL, imageTag.Name)
+
+	env := []interface{}{
+		map[string]interface{}{
+			"name":  "NOTEBOOK_ARGS",
+			"value": notebookArgs,
+		},
+		map[string]interface{}{
+			"name":  "JUPYTER_IMAGE",
+			"value": imageFull,
+		},
+	}
+	env = append(env, extraEnv...)
+
+	var envFrom []interface{}
+	for _, connection := range input.Connections {
+		envFrom = append(envFrom, connectionEnvFrom(connection))
+	}
+
+	annotations := map[string]interface{}{
+		"opendatahub.io/image-display-name":                                input.ImageDisplayName,
+		"openshift.io/display-name":                                        input.WorkbenchName,
+		"openshift.io/description":                                         "Created via MCP",
+		"notebooks.opendatahub.io/inject-auth":                             "true",
+		"notebooks.opendatahub.io/last-image-selection":                    fmt.Sprintf("%s:%s", imageName, imageTag.Name),
+		"notebooks.opendatahub.io/last-image-version-git-commit-selection": imageTag.BuildCommit,
+		"opendatahub.io/hardware-profile-name":                             "default-profile",
+		"opendatahub.io/hardware-profile-namespace":                        "redhat-ods-applications",
+	}
+	if len(input.Connections) > 0 {
+		annotations["opendatahub.io/connections"] = connectionsAnnotation(input.Namespace, input.Connections)
 	}
 
 	notebook := &unstructured.Unstructured{
@@ -50,16 +147,7 @@ func CreateWorkbench(ctx context.Context, req *mcp.CallToolRequest, input Create
 					"opendatahub.io/dashboard":   "true",
 					"opendatahub.io/odh-managed": "true",
 				},
-				"annotations": map[string]interface{}{
-					"opendatahub.io/image-display-name":                                input.ImageDisplayName,
-					"openshift.io/display-name":                                        input.WorkbenchName,
-					"openshift.io/description":                                         "Created via MCP",
-					"notebooks.opendatahub.io/inject-auth":                             "true",
-					"notebooks.opendatahub.io/last-image-selection":                    fmt.Sprintf("%s:%s", imageName, input.ImageTag),
-					"notebooks.opendatahub.io/last-image-version-git-commit-selection": gitCommit,
-					"opendatahub.io/hardware-profile-name":                             "default-profile",
-					"opendatahub.io/hardware-profile-namespace":                        "redhat-ods-applications",
-				},
+				"annotations": annotations,
 			},
 			"spec": map[string]interface{}{
 				"template": map[string]interface{}{
@@ -74,21 +162,13 @@ func CreateWorkbench(ctx context.Context, req *mcp.CallToolRequest, input Create
 								"workingDir":      "/opt/app-root/src",
 								"ports": []interface{}{
 									map[string]interface{}{
-										"containerPort": 8888,
+										"containerPort": int64(8888),
 										"name":          "notebook-port",
 										"protocol":      "TCP",
 									},
 								},
-								"env": []interface{}{
-									map[string]interface{}{
-										"name":  "NOTEBOOK_ARGS",
-										"value": notebookArgs,
-									},
-									map[string]interface{}{
-										"name":  "JUPYTER_IMAGE",
-										"value": imageFull,
-									},
-								},
+								"env":     env,
+								"envFrom": envFrom,
 								"resources": map[string]interface{}{
 									"limits": map[string]interface{}{
 										"cpu":    "2",
@@ -99,32 +179,10 @@ func CreateWorkbench(ctx context.Context, req *mcp.CallToolRequest, input Create
 										"memory": "4Gi",
 									},
 								},
-								"volumeMounts": []interface{}{
-									map[string]interface{}{
-										"mountPath": "/opt/app-root/src/",
-										"name":      "storage-volume",
-									},
-									map[string]interface{}{
-										"mountPath": "/dev/shm",
-										"name":      "shm",
-									},
-								},
-							},
-						},
-						"volumes": []interface{}{
-							map[string]interface{}{
-								"name": "storage-volume",
-								"persistentVolumeClaim": map[string]interface{}{
-									"claimName": input.WorkbenchName,
-								},
-							},
-							map[string]interface{}{
-								"name": "shm",
-								"emptyDir": map[string]interface{}{
-									"medium": "Memory",
-								},
+								"volumeMounts": volumeMounts,
 							},
 						},
+						"volumes": volumes,
 					},
 				},
 			},
@@ -136,11 +194,33 @@ func CreateWorkbench(ctx context.Context, req *mcp.CallToolRequest, input Create
 		return nil, WorkbenchOutput{}, fmt.Errorf("failed to create notebook: %v", err)
 	}
 
-	return nil, WorkbenchOutput{Message: "Workbench was succesfully created!"}, nil
+	return nil, WorkbenchOutput{Message: fmt.Sprintf("Workbench was succesfully created with image %s:%s!", input.ImageDisplayName, imageTag.Name)}, nil
 }
 
 func createPersistentVolumeClaim(ctx context.Context, dyn dynamic.Interface, namespace, name, size string) error {
-	pvc := &unstructured.Unstructured{
+	pvc := newPersistentVolumeClaim(namespace, name, size, "")
+
+	_, err := dyn.Resource(pvcGVR).Namespace(namespace).Create(ctx, pvc, metav1.CreateOptions{})
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+func newPersistentVolumeClaim(namespace, name, size, storageClass string) *unstructured.Unstructured {
+	spec := map[string]interface{}{
+		"accessModes": []interface{}{"ReadWriteOnce"},
+		"resources": map[string]interface{}{
+			"requests": map[string]interface{}{
+				"storage": size,
+			},
+		},
+	}
+	if storageClass != "" {
+		spec["storageClassName"] = storageClass
+	}
+
+	return &unstructured.Unstructured{
 		Object: map[string]interface{}{
 			"apiVersion": "v1",
 			"kind":       "PersistentVolumeClaim",
@@ -151,20 +231,7 @@ func createPersistentVolumeClaim(ctx context.Context, dyn dynamic.Interface, nam
 					"opendatahub.io/dashboard": "true",
 				},
 			},
-			"spec": map[string]interface{}{
-				"accessModes": []interface{}{"ReadWriteOnce"},
-				"resources": map[string]interface{}{
-					"requests": map[string]interface{}{
-						"storage": size,
-					},
-				},
-			},
+			"spec": spec,
 		},
 	}
-
-	_, err := dyn.Resource(pvcGVR).Namespace(namespace).Create(ctx, pvc, metav1.CreateOptions{})
-	if err != nil && !errors.IsAlreadyExists(err) {
-		return err
-	}
-	return nil
 }
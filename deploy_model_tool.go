@@ -0,0 +1,375 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// namespace holding the platform wide notebook images and serving runtime templates
+const applicationsNamespace = "redhat-ods-applications"
+
+// Deploys a model with KServe using a ServingRuntime instantiated from one of the platform templates
+func DeployModel(ctx context.Context, req *mcp.CallToolRequest, input DeployModelInput) (*mcp.CallToolResult, ModelDeploymentOutput, error) {
+	out, err := deployModel(ctx, input, nil)
+	return nil, out, err
+}
+
+// deployModel creates the ServingRuntime and InferenceService, extraLabels are set on the InferenceService
+func deployModel(ctx context.Context, input DeployModelInput, extraLabels map[string]string) (ModelDeploymentOutput, error) {
+	if (input.ConnectionName == "") == (input.StorageURI == "") {
+		return ModelDeploymentOutput{}, fmt.Errorf("exactly one of connection name or storage URI has to be set")
+	}
+
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return ModelDeploymentOutput{}, err
+	}
+
+	template, servingRuntime, err := getServingRuntimeTemplate(ctx, dyn, input.RuntimeTemplate)
+	if err != nil {
+		return ModelDeploymentOutput{}, err
+	}
+
+	formats := servingRuntimeFormats(*servingRuntime)
+	modelFormat := input.ModelFormat
+	if modelFormat == "" && len(formats) > 0 {
+		modelFormat = formats[0]
+	}
+	if modelFormat == "" {
+		return ModelDeploymentOutput{}, fmt.Errorf("model format is required for runtime %s", template.GetName())
+	}
+	if len(formats) > 0 && !containsFold(formats, modelFormat) {
+		return ModelDeploymentOutput{}, fmt.Errorf("runtime %s does not support model format %s, supported formats: %s", template.GetName(), modelFormat, strings.Join(formats, ", "))
+	}
+
+	if input.ConnectionName != "" {
+		if _, err := getConnection(ctx, dyn, input.Namespace, input.ConnectionName); err != nil {
+			return ModelDeploymentOutput{}, err
+		}
+	}
+
+	displayName := input.DisplayName
+	if displayName == "" {
+		displayName = input.ModelName
+	}
+
+	// the runtime is a per deployment copy of the template object
+	servingRuntime.SetName(input.ModelName)
+	servingRuntime.SetNamespace(input.Namespace)
+	servingRuntime.SetLabels(map[string]string{
+		"opendatahub.io/dashboard": "true",
+	})
+	runtimeAnnotations := servingRuntime.GetAnnotations()
+	if runtimeAnnotations == nil {
+		runtimeAnnotations = map[string]string{}
+	}
+	runtimeAnnotations["opendatahub.io/template-name"] = template.GetName()
+	runtimeAnnotations["opendatahub.io/template-display-name"] = runtimeAnnotations["openshift.io/display-name"]
+	runtimeAnnotations["openshift.io/display-name"] = displayName
+	runtimeAnnotations["opendatahub.io/serving-runtime-scope"] = "global"
+	if input.AcceleratorProfile != "" {
+		runtimeAnnotations["opendatahub.io/accelerator-name"] = input.AcceleratorProfile
+	}
+	servingRuntime.SetAnnotations(runtimeAnnotations)
+
+	_, err = dyn.Resource(servingRuntimesGVR).Namespace(input.Namespace).Create(ctx, servingRuntime, metav1.CreateOptions{})
+	if err != nil {
+		return ModelDeploymentOutput{}, fmt.Errorf("failed to create serving runtime: %v", err)
+	}
+
+	isvc := newInferenceService(input, displayName, modelFormat, extraLabels)
+	if input.TokenAuth {
+		if err := createModelServiceAccount(ctx, dyn, input.Namespace, input.ModelName); err != nil {
+			_ = dyn.Resource(servingRuntimesGVR).Namespace(input.Namespace).Delete(ctx, input.ModelName, metav1.DeleteOptions{})
+			return ModelDeploymentOutput{}, err
+		}
+	}
+
+	_, err = dyn.Resource(inferenceServicesGVR).Namespace(input.Namespace).Create(ctx, isvc, metav1.CreateOptions{})
+	if err != nil {
+		// do not leave an orphaned runtime or token auth objects behind
+		_ = dyn.Resource(servingRuntimesGVR).Namespace(input.Namespace).Delete(ctx, input.ModelName, metav1.DeleteOptions{})
+		if input.TokenAuth {
+			_ = deleteModelServiceAccount(ctx, dyn, input.Namespace, input.ModelName)
+		}
+		return ModelDeploymentOutput{}, fmt.Errorf("failed to create inference service: %v", err)
+	}
+
+	return ModelDeploymentOutput{Message: fmt.Sprintf("Model %s was succesfully deployed with runtime %s!", input.ModelName, template.GetName())}, nil
+}
+
+func newInferenceService(input DeployModelInput, displayName, modelFormat string, extraLabels map[string]string) *unstructured.Unstructured {
+	replicas := int64(input.Replicas)
+	if replicas == 0 {
+		replicas = 1
+	}
+
+	requests := map[string]interface{}{"cpu": "1", "memory": "4Gi"}
+	limits := map[string]interface{}{"cpu": "2", "memory": "8Gi"}
+	if input.CPU != "" {
+		requests["cpu"] = input.CPU
+		limits["cpu"] = input.CPU
+	}
+	if input.Memory != "" {
+		requests["memory"] = input.Memory
+		limits["memory"] = input.Memory
+	}
+	if input.Accelerator != "" {
+		count := input.AcceleratorCount
+		if count == 0 {
+			count = 1
+		}
+		requests[input.Accelerator] = fmt.Sprintf("%d", count)
+		limits[input.Accelerator] = fmt.Sprintf("%d", count)
+	}
+
+	model := map[string]interface{}{
+		"modelFormat": map[string]interface{}{
+			"name": modelFormat,
+		},
+		"runtime": input.ModelName,
+		"resources": map[string]interface{}{
+			"requests": requests,
+			"limits":   limits,
+		},
+	}
+	if input.ConnectionName != "" {
+		model["storage"] = map[string]interface{}{
+			"key":  input.ConnectionName,
+			"path": input.ModelPath,
+		}
+	} else {
+		model["storageUri"] = input.StorageURI
+	}
+
+	predictor := map[string]interface{}{
+		"minReplicas": replicas,
+		"maxReplicas": replicas,
+		"model":       model,
+	}
+
+	labels := map[string]interface{}{
+		"opendatahub.io/dashboard": "true",
+	}
+	if input.ExternalRoute {
+		labels["networking.kserve.io/visibility"] = "exposed"
+	}
+	for key, value := range extraLabels {
+		labels[key] = value
+	}
+	annotations := map[string]interface{}{
+		"openshift.io/display-name":        displayName,
+		"serving.kserve.io/deploymentMode": "RawDeployment",
+	}
+	if input.TokenAuth {
+		annotations["security.opendatahub.io/enable-auth"] = "true"
+		predictor["serviceAccountName"] = modelServiceAccountName(input.ModelName)
+	}
+
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "serving.kserve.io/v1beta1",
+			"kind":       "InferenceService",
+			"metadata": map[string]interface{}{
+				"name":        input.ModelName,
+				"namespace":   input.Namespace,
+				"labels":      labels,
+				"annotations": annotations,
+			},
+			"spec": map[string]interface{}{
+				"predictor": predictor,
+			},
+		},
+	}
+}
+
+// createModelServiceAccount creates the service account whose tokens may query a model with token auth
+func createModelServiceAccount(ctx context.Context, dyn dynamic.Interface, namespace, modelName string) error {
+	name := modelServiceAccountName(modelName)
+	serviceAccount := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ServiceAccount",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": namespace,
+				"labels": map[string]interface{}{
+					"opendatahub.io/dashboard": "true",
+				},
+			},
+		},
+	}
+	role := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "rbac.authorization.k8s.io/v1",
+			"kind":       "Role",
+			"metadata": map[string]interface{}{
+				"name":      modelName + "-view-role",
+				"namespace": namespace,
+				"labels": map[string]interface{}{
+					"opendatahub.io/dashboard": "true",
+				},
+			},
+			"rules": []interface{}{
+				map[string]interface{}{
+					"apiGroups":     []interface{}{"serving.kserve.io"},
+					"resources":     []interface{}{"inferenceservices"},
+					"resourceNames": []interface{}{modelName},
+					"verbs":         []interface{}{"get"},
+				},
+			},
+		},
+	}
+	binding := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "rbac.authorization.k8s.io/v1",
+			"kind":       "RoleBinding",
+			"metadata": map[string]interface{}{
+				"name":      modelName + "-view",
+				"namespace": namespace,
+				"labels": map[string]interface{}{
+					"opendatahub.io/dashboard": "true",
+				},
+			},
+			"roleRef": map[string]interface{}{
+				"apiGroup": "rbac.authorization.k8s.io",
+				"kind":     "Role",
+				"name":     modelName + "-view-role",
+			},
+			"subjects": []interface{}{
+				map[string]interface{}{
+					"kind":      "ServiceAccount",
+					"name":      name,
+					"namespace": namespace,
+				},
+			},
+		},
+	}
+
+	// objects created before a failure are removed again so a retry does not hit AlreadyExists
+	objects := []struct {
+		gvr    schema.GroupVersionResource
+		object *unstructured.Unstructured
+	}{
+		{serviceAccountsGVR, serviceAccount},
+		{rolesGVR, role},
+		{roleBindingsGVR, binding},
+	}
+	for i, o := range objects {
+		if _, err := dyn.Resource(o.gvr).Namespace(namespace).Create(ctx, o.object, metav1.CreateOptions{}); err != nil {
+			for j := i - 1; j >= 0; j-- {
+				_ = dyn.Resource(objects[j].gvr).Namespace(namespace).Delete(ctx, objects[j].object.GetName(), metav1.DeleteOptions{})
+			}
+			return fmt.Errorf("failed to create %s %s: %v", o.gvr.Resource, o.object.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// deleteModelServiceAccount removes the token auth objects created by createModelServiceAccount,
+// objects that only match the naming pattern but were not created by the dashboard are left alone
+func deleteModelServiceAccount(ctx context.Context, dyn dynamic.Interface, namespace, modelName string) error {
+	for _, object := range []struct {
+		gvr  schema.GroupVersionResource
+		name string
+	}{
+		{serviceAccountsGVR, modelServiceAccountName(modelName)},
+		{rolesGVR, modelName + "-view-role"},
+		{roleBindingsGVR, modelName + "-view"},
+	} {
+		existing, err := dyn.Resource(object.gvr).Namespace(namespace).Get(ctx, object.name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get %s %s: %v", object.gvr.Resource, object.name, err)
+		}
+		if existing.GetLabels()["opendatahub.io/dashboard"] != "true" {
+			continue
+		}
+		err = dyn.Resource(object.gvr).Namespace(namespace).Delete(ctx, object.name, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete %s %s: %v", object.gvr.Resource, object.name, err)
+		}
+	}
+	return nil
+}
+
+func modelServiceAccountName(modelName string) string {
+	return modelName + "-sa"
+}
+
+// getServingRuntimeTemplate finds a serving runtime template by name or display name
+// and returns it together with a copy of the ServingRuntime it contains
+func getServingRuntimeTemplate(ctx context.Context, dyn dynamic.Interface, name string) (*unstructured.Unstructured, *unstructured.Unstructured, error) {
+	templates, err := listServingRuntimeTemplates(ctx, dyn)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, template := range templates {
+		servingRuntime := templateServingRuntime(template)
+		if servingRuntime == nil {
+			continue
+		}
+		if template.GetName() == name || strings.EqualFold(servingRuntime.GetAnnotations()["openshift.io/display-name"], name) {
+			return &template, servingRuntime, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("serving runtime template not found: %s", name)
+}
+
+func listServingRuntimeTemplates(ctx context.Context, dyn dynamic.Interface) ([]unstructured.Unstructured, error) {
+	templates, err := dyn.Resource(templatesGVR).Namespace(applicationsNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "opendatahub.io/dashboard=true",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list serving runtime templates: %v", err)
+	}
+	return templates.Items, nil
+}
+
+// templateServingRuntime returns the ServingRuntime object of a template, nil when it has none
+func templateServingRuntime(template unstructured.Unstructured) *unstructured.Unstructured {
+	objects, _, _ := unstructured.NestedSlice(template.Object, "objects")
+	for _, o := range objects {
+		object, ok := o.(map[string]interface{})
+		if ok && object["kind"] == "ServingRuntime" {
+			return &unstructured.Unstructured{Object: object}
+		}
+	}
+	return nil
+}
+
+func servingRuntimeFormats(servingRuntime unstructured.Unstructured) []string {
+	formatsRaw, _, _ := unstructured.NestedSlice(servingRuntime.Object, "spec", "supportedModelFormats")
+
+	var formats []string
+	for _, f := range formatsRaw {
+		format, ok := f.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name, _ := format["name"].(string); name != "" {
+			formats = append(formats, name)
+		}
+	}
+	return formats
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// imageRequirement is one term of a Find Images query - f.e. pytorch>=2.3 or cuda
+type imageRequirement struct {
+	name    string
+	op      string
+	version string
+}
+
+// name followed by an optional operator and version, a version without operator means ==
+var imageRequirementPattern = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9 ._+-]*?)\s*(>=|<=|==|=|>|<)?\s*v?([0-9][0-9A-Za-z.]*)?$`)
+
+type imageMatch struct {
+	image   ImageDef
+	tag     ImageTagDef
+	matched []string
+	missed  []string
+}
+
+// Ranks notebook image tags by how many of the requested packages and capabilities they provide
+func FindImages(ctx context.Context, req *mcp.CallToolRequest, input FindImagesInput) (*mcp.CallToolResult, FindImagesOutput, error) {
+	requirements, err := parseImageQuery(input.Query)
+	if err != nil {
+		return nil, FindImagesOutput{}, err
+	}
+	limit := input.Limit
+	if limit <= 0 {
+		limit = 5
+	}
+
+	images, err := GetImages(ctx)
+	if err != nil {
+		return nil, FindImagesOutput{}, err
+	}
+
+	var matches []imageMatch
+	for _, image := range images {
+		for _, tag := range image.Tags {
+			match := imageMatch{image: image, tag: tag}
+			for _, requirement := range requirements {
+				if found, ok := matchImageRequirement(image, tag, requirement); ok {
+					match.matched = append(match.matched, found)
+				} else {
+					match.missed = append(match.missed, requirement.String())
+				}
+			}
+			if len(match.matched) > 0 {
+				matches = append(matches, match)
+			}
+		}
+	}
+	if len(matches) == 0 {
+		return nil, FindImagesOutput{Images: fmt.Sprintf("No images match %s", input.Query)}, nil
+	}
+
+	// more matched terms first, then recommended, current and newer tags
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if len(a.matched) != len(b.matched) {
+			return len(a.matched) > len(b.matched)
+		}
+		if a.tag.Recommended != b.tag.Recommended {
+			return a.tag.Recommended
+		}
+		if a.tag.Outdated != b.tag.Outdated {
+			return !a.tag.Outdated
+		}
+		return newerImageTag(a.tag, b.tag)
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	msg := ""
+	for _, match := range matches {
+		msg += fmt.Sprintf("- ImageDisplayName: %s, ImageTag: %s", match.image.Name, match.tag.Name)
+		if match.tag.Recommended {
+			msg += " (recommended)"
+		}
+		if match.tag.Outdated {
+			msg += " (outdated)"
+		}
+		msg += fmt.Sprintf(" - matches %d/%d: %s", len(match.matched), len(requirements), strings.Join(match.matched, ", "))
+		if len(match.missed) > 0 {
+			msg += fmt.Sprintf("; missing: %s", strings.Join(match.missed, ", "))
+		}
+		msg += "\n"
+	}
+	return nil, FindImagesOutput{Images: msg}, nil
+}
+
+func parseImageQuery(query string) ([]imageRequirement, error) {
+	var requirements []imageRequirement
+	for _, term := range strings.Split(query, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		parts := imageRequirementPattern.FindStringSubmatch(term)
+		if parts == nil {
+			return nil, fmt.Errorf("invalid query term %q, expected a name with an optional version like pytorch>=2.3", term)
+		}
+		requirement := imageRequirement{name: strings.TrimSpace(parts[1]), op: parts[2], version: parts[3]}
+		if requirement.op != "" && requirement.version == "" {
+			return nil, fmt.Errorf("query term %q has an operator but no version", term)
+		}
+		if requirement.op == "" && requirement.version != "" {
+			requirement.op = "=="
+		}
+		requirements = append(requirements, requirement)
+	}
+	if len(requirements) == 0 {
+		return nil, fmt.Errorf("query is empty")
+	}
+	return requirements, nil
+}
+
+func (r imageRequirement) String() string {
+	return r.name + r.op + r.version
+}
+
+// matchImageRequirement returns the package satisfying the requirement - f.e. PyTorch 2.4. Terms
+// without a version are capabilities, which may also be found in the image name or description.
+func matchImageRequirement(image ImageDef, tag ImageTagDef, requirement imageRequirement) (string, bool) {
+	packages := append([]ImagePackage{}, tag.Software...)
+	packages = append(packages, tag.Packages...)
+	for _, pkg := range packages {
+		if !strings.Contains(normalizePackageName(pkg.Name), normalizePackageName(requirement.name)) {
+			continue
+		}
+		if requirement.version == "" || versionSatisfies(pkg.Version, requirement.op, requirement.version) {
+			return fmt.Sprintf("%s %s", pkg.Name, strings.TrimPrefix(pkg.Version, "v")), true
+		}
+	}
+
+	if requirement.version == "" {
+		name := normalizePackageName(requirement.name)
+		if strings.Contains(normalizePackageName(image.Name), name) || strings.Contains(normalizePackageName(image.Description), name) {
+			return requirement.name, true
+		}
+	}
+	return "", false
+}
+
+func normalizePackageName(name string) string {
+	return strings.NewReplacer(" ", "", "-", "", "_", "", ".", "").Replace(strings.ToLower(name))
+}
+
+// versionSatisfies compares dotted versions numerically. == matches by prefix, so python 3.12
+// is satisfied by 3.12.4.
+func versionSatisfies(actual, op, required string) bool {
+	actualParts := versionParts(actual)
+	requiredParts := versionParts(required)
+	if len(actualParts) == 0 {
+		return false
+	}
+
+	switch op {
+	case "==", "=":
+		if len(actualParts) < len(requiredParts) {
+			return false
+		}
+		for i := range requiredParts {
+			if actualParts[i] != requiredParts[i] {
+				return false
+			}
+		}
+		return true
+	}
+
+	cmp := compareVersionParts(actualParts, requiredParts)
+	switch op {
+	case ">=":
+		return cmp >= 0
+	case ">":
+		return cmp > 0
+	case "<=":
+		return cmp <= 0
+	case "<":
+		return cmp < 0
+	}
+	return false
+}
+
+// versionParts returns the numeric prefix of every dotted component - f.e. v2.4.0+cu121 is 2, 4, 0
+func versionParts(version string) []int {
+	var parts []int
+	for _, component := range strings.Split(strings.TrimPrefix(strings.TrimSpace(version), "v"), ".") {
+		digits := component
+		for i, c := range component {
+			if c < '0' || c > '9' {
+				digits = component[:i]
+				break
+			}
+		}
+		number, err := strconv.Atoi(digits)
+		if err != nil {
+			break
+		}
+		parts = append(parts, number)
+	}
+	return parts
+}
+
+// newerImageTag compares tag names as versions, image streams name tags like 2024.2 or 2025.1
+func newerImageTag(a, b ImageTagDef) bool {
+	return compareVersionParts(versionParts(a.Name), versionParts(b.Name)) > 0
+}
+
+func compareVersionParts(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
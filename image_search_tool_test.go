@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestParseImageQuery(t *testing.T) {
+	requirements, err := parseImageQuery("pytorch>=2.3, python 3.12, cuda,scikit-learn==1.5")
+	if err != nil {
+		t.Fatalf("parseImageQuery returned error: %v", err)
+	}
+	expected := []imageRequirement{
+		{name: "pytorch", op: ">=", version: "2.3"},
+		{name: "python", op: "==", version: "3.12"},
+		{name: "cuda"},
+		{name: "scikit-learn", op: "==", version: "1.5"},
+	}
+	if len(requirements) != len(expected) {
+		t.Fatalf("expected %d requirements, got: %+v", len(expected), requirements)
+	}
+	for i := range expected {
+		if requirements[i] != expected[i] {
+			t.Errorf("expected %+v, got %+v", expected[i], requirements[i])
+		}
+	}
+
+	for _, invalid := range []string{"", " , ", "pytorch>=", ">=2.3"} {
+		if _, err := parseImageQuery(invalid); err == nil {
+			t.Errorf("expected error for query %q", invalid)
+		}
+	}
+}
+
+func TestVersionSatisfies(t *testing.T) {
+	for _, c := range []struct {
+		actual, op, required string
+		expected             bool
+	}{
+		{"2.4", ">=", "2.3", true},
+		{"v2.2", ">=", "2.3", false},
+		{"3.12.4", "==", "3.12", true},
+		{"3.1", "==", "3.12", false},
+		{"3.11", "<", "3.12", true},
+		{"2.4.0+cu121", ">", "2.4", false},
+		{"unknown", ">=", "1", false},
+	} {
+		if got := versionSatisfies(c.actual, c.op, c.required); got != c.expected {
+			t.Errorf("versionSatisfies(%q, %q, %q) = %v, expected %v", c.actual, c.op, c.required, got, c.expected)
+		}
+	}
+}
+
+func TestFindImages(t *testing.T) {
+	orig := getDynamicClient
+	defer func() { getDynamicClient = orig }()
+
+	datascience := newUnstructuredImageStream("s2i-generic-data-science-notebook", "Standard Data Science", "2024.2")
+	setImageStreamTagAnnotations(datascience, "2024.2", map[string]string{
+		"opendatahub.io/notebook-software":            `[{"name":"Python","version":"v3.12"}]`,
+		"opendatahub.io/notebook-python-dependencies": `[{"name":"Scikit-learn","version":"1.5"}]`,
+	})
+
+	scheme := runtime.NewScheme()
+	client := dynamicfake.NewSimpleDynamicClient(scheme, newPyTorchImageStream(), datascience)
+	getDynamicClient = func() (dynamic.Interface, error) {
+		return client, nil
+	}
+	ctx := context.Background()
+
+	_, out, err := FindImages(ctx, nil, FindImagesInput{Query: "pytorch>=2.3, python 3.11, cuda"})
+	if err != nil {
+		t.Fatalf("FindImages returned error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.Images), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected two matching tags, got:\n%s", out.Images)
+	}
+	if lines[0] != "- ImageDisplayName: PyTorch, ImageTag: 2024.2 (recommended) - matches 3/3: PyTorch 2.4, Python 3.11, CUDA 12.1" {
+		t.Errorf("unexpected best match: %q", lines[0])
+	}
+	if lines[1] != "- ImageDisplayName: PyTorch, ImageTag: 2024.1 (outdated) - matches 1/3: CUDA 12.1; missing: pytorch>=2.3, python==3.11" {
+		t.Errorf("unexpected second match: %q", lines[1])
+	}
+
+	_, out, err = FindImages(ctx, nil, FindImagesInput{Query: "python 3.12, scikit-learn", Limit: 1})
+	if err != nil {
+		t.Fatalf("FindImages returned error: %v", err)
+	}
+	if out.Images != "- ImageDisplayName: Standard Data Science, ImageTag: 2024.2 - matches 2/2: Python 3.12, Scikit-learn 1.5\n" {
+		t.Errorf("unexpected matches: %q", out.Images)
+	}
+
+	_, out, err = FindImages(ctx, nil, FindImagesInput{Query: "rocm"})
+	if err != nil {
+		t.Fatalf("FindImages returned error: %v", err)
+	}
+	if out.Images != "No images match rocm" {
+		t.Errorf("unexpected message: %q", out.Images)
+	}
+}
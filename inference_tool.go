@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Sends an OpenAI compatible or KServe v2 request to a deployed model and reports its response and latency
+func RunInference(ctx context.Context, req *mcp.CallToolRequest, input RunInferenceInput) (*mcp.CallToolResult, RunInferenceOutput, error) {
+	protocol := input.Protocol
+	if protocol == "" {
+		protocol = "openai-chat"
+	}
+
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, RunInferenceOutput{}, err
+	}
+
+	isvc, err := dyn.Resource(inferenceServicesGVR).Namespace(input.Namespace).Get(ctx, input.ModelName, metav1.GetOptions{})
+	if err != nil {
+		return nil, RunInferenceOutput{}, fmt.Errorf("failed to get model deployment %s: %v", input.ModelName, err)
+	}
+
+	baseURL, _, _ := unstructured.NestedString(isvc.Object, "status", "url")
+	if input.UseInternalURL {
+		baseURL, _, _ = unstructured.NestedString(isvc.Object, "status", "address", "url")
+	}
+	if baseURL == "" {
+		return nil, RunInferenceOutput{}, fmt.Errorf("model deployment %s has no URL yet, it is %s", input.ModelName, inferenceServiceState(*isvc))
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	var url string
+	var body interface{}
+	switch protocol {
+	case "openai-chat":
+		url = baseURL + "/v1/chat/completions"
+		request := map[string]interface{}{
+			"model": input.ModelName,
+			"messages": []interface{}{
+				map[string]interface{}{"role": "user", "content": input.Prompt},
+			},
+		}
+		if input.MaxTokens > 0 {
+			request["max_tokens"] = input.MaxTokens
+		}
+		body = request
+	case "openai-completions":
+		url = baseURL + "/v1/completions"
+		request := map[string]interface{}{
+			"model":  input.ModelName,
+			"prompt": input.Prompt,
+		}
+		if input.MaxTokens > 0 {
+			request["max_tokens"] = input.MaxTokens
+		}
+		body = request
+	case "kserve-v2":
+		url = fmt.Sprintf("%s/v2/models/%s/infer", baseURL, input.ModelName)
+		var inputs []interface{}
+		if err := json.Unmarshal([]byte(input.Inputs), &inputs); err != nil {
+			return nil, RunInferenceOutput{}, fmt.Errorf("inputs must be a JSON array of input tensors: %v", err)
+		}
+		body = map[string]interface{}{"inputs": inputs}
+	default:
+		return nil, RunInferenceOutput{}, fmt.Errorf("protocol must be openai-chat, openai-completions or kserve-v2, got: %s", protocol)
+	}
+
+	token := input.Token
+	if token == "" && isvc.GetAnnotations()["security.opendatahub.io/enable-auth"] == "true" {
+		token, err = requestModelToken(ctx, input.Namespace, input.ModelName)
+		if err != nil {
+			return nil, RunInferenceOutput{}, err
+		}
+	}
+
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return nil, RunInferenceOutput{}, fmt.Errorf("failed to marshal inference request: %v", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, RunInferenceOutput{}, fmt.Errorf("failed to build inference request: %v", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	start := time.Now()
+	resp, err := defaultHTTPClient.Do(httpReq)
+	if err != nil {
+		return nil, RunInferenceOutput{}, fmt.Errorf("failed to call %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	latency := time.Since(start)
+	if err != nil {
+		return nil, RunInferenceOutput{}, fmt.Errorf("failed to read inference response: %v", err)
+	}
+
+	return nil, RunInferenceOutput{
+		Response:   inferenceResponseText(protocol, respBody),
+		StatusCode: resp.StatusCode,
+		LatencyMs:  latency.Milliseconds(),
+		URL:        url,
+	}, nil
+}
+
+// requestModelToken issues a short lived token of the service account created for token auth
+func requestModelToken(ctx context.Context, namespace, modelName string) (string, error) {
+	clientset, err := getClientSet()
+	if err != nil {
+		return "", err
+	}
+
+	expiration := int64(600)
+	tokenRequest, err := clientset.CoreV1().ServiceAccounts(namespace).CreateToken(ctx, modelServiceAccountName(modelName), &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{ExpirationSeconds: &expiration},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to request token for model %s: %v", modelName, err)
+	}
+	return tokenRequest.Status.Token, nil
+}
+
+// inferenceResponseText extracts the generated text from OpenAI responses, other responses are returned as they are
+func inferenceResponseText(protocol string, body []byte) string {
+	var response struct {
+		Choices []struct {
+			Text    string `json:"text"`
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if protocol == "kserve-v2" || json.Unmarshal(body, &response) != nil || len(response.Choices) == 0 {
+		return string(body)
+	}
+	if protocol == "openai-chat" {
+		return response.Choices[0].Message.Content
+	}
+	return response.Choices[0].Text
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestRunInference(t *testing.T) {
+	origDynamic := getDynamicClient
+	origClientSet := getClientSet
+	defer func() {
+		getDynamicClient = origDynamic
+		getClientSet = origClientSet
+	}()
+
+	// stands in for a vLLM server with token auth in front of it
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer sa-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/chat/completions":
+			var request struct {
+				Model    string `json:"model"`
+				Messages []struct {
+					Content string `json:"content"`
+				} `json:"messages"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Model != "granite" || len(request.Messages) != 1 {
+				t.Errorf("unexpected chat request: %+v (%v)", request, err)
+			}
+			_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"Hello from granite"}}]}`))
+		case "/v2/models/granite/infer":
+			_, _ = w.Write([]byte(`{"outputs":[{"name":"output","shape":[1],"datatype":"FP32","data":[0.5]}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	isvc := newUnstructuredInferenceService("granite", "ns1", server.URL, true)
+	isvc.SetAnnotations(map[string]string{"security.opendatahub.io/enable-auth": "true"})
+	scheme := runtime.NewScheme()
+	client := dynamicfake.NewSimpleDynamicClient(scheme, isvc)
+	getDynamicClient = func() (dynamic.Interface, error) {
+		return client, nil
+	}
+
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("create", "serviceaccounts", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "token" {
+			return false, nil, nil
+		}
+		return true, &authenticationv1.TokenRequest{Status: authenticationv1.TokenRequestStatus{Token: "sa-token"}}, nil
+	})
+	getClientSet = func() (kubernetes.Interface, error) {
+		return clientset, nil
+	}
+	ctx := context.Background()
+
+	_, out, err := RunInference(ctx, nil, RunInferenceInput{Namespace: "ns1", ModelName: "granite", Prompt: "Hi"})
+	if err != nil {
+		t.Fatalf("RunInference returned error: %v", err)
+	}
+	if out.StatusCode != http.StatusOK || out.Response != "Hello from granite" {
+		t.Errorf("unexpected chat result: %+v", out)
+	}
+	if out.URL != server.URL+"/v1/chat/completions" {
+		t.Errorf("unexpected URL: %q", out.URL)
+	}
+
+	_, out, err = RunInference(ctx, nil, RunInferenceInput{
+		Namespace: "ns1",
+		ModelName: "granite",
+		Protocol:  "kserve-v2",
+		Inputs:    `[{"name":"input","shape":[1,1],"datatype":"FP32","data":[1]}]`,
+	})
+	if err != nil {
+		t.Fatalf("RunInference returned error: %v", err)
+	}
+	if out.StatusCode != http.StatusOK || out.Response != `{"outputs":[{"name":"output","shape":[1],"datatype":"FP32","data":[0.5]}]}` {
+		t.Errorf("unexpected kserve-v2 result: %+v", out)
+	}
+
+	_, out, err = RunInference(ctx, nil, RunInferenceInput{Namespace: "ns1", ModelName: "granite", Prompt: "Hi", Token: "wrong"})
+	if err != nil {
+		t.Fatalf("RunInference returned error: %v", err)
+	}
+	if out.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected unauthorized status with explicit wrong token, got: %d", out.StatusCode)
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Lists Kueue LocalQueues and the ClusterQueues behind them with quota usage per resource flavor
+func ListQueues(ctx context.Context, req *mcp.CallToolRequest, input ListQueuesInput) (*mcp.CallToolResult, ListQueuesOutput, error) {
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, ListQueuesOutput{}, err
+	}
+
+	localQueues, err := dyn.Resource(localQueuesGVR).Namespace(input.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, ListQueuesOutput{}, fmt.Errorf("failed to list local queues: %v", err)
+	}
+	// project users usually cannot list the cluster scoped queues, their local queues are still listed
+	clusterQueues, err := dyn.Resource(clusterQueuesGVR).List(ctx, metav1.ListOptions{})
+	if err != nil && !errors.IsForbidden(err) {
+		return nil, ListQueuesOutput{}, fmt.Errorf("failed to list cluster queues: %v", err)
+	}
+
+	msg := "Local queues:\n"
+	if len(localQueues.Items) == 0 {
+		msg += "- none\n"
+	}
+	usedClusterQueues := map[string]bool{}
+	for _, lq := range localQueues.Items {
+		clusterQueue, _, _ := unstructured.NestedString(lq.Object, "spec", "clusterQueue")
+		usedClusterQueues[clusterQueue] = true
+		pending, _, _ := unstructured.NestedInt64(lq.Object, "status", "pendingWorkloads")
+		admitted, _, _ := unstructured.NestedInt64(lq.Object, "status", "admittedWorkloads")
+		msg += fmt.Sprintf("- %s/%s -> cluster queue %s: %d admitted, %d pending workloads\n", lq.GetNamespace(), lq.GetName(), clusterQueue, admitted, pending)
+	}
+
+	msg += "Cluster queues:\n"
+	if clusterQueues == nil {
+		msg += "- quota details are unavailable, listing cluster queues is not permitted\n"
+		return nil, ListQueuesOutput{Queues: msg}, nil
+	}
+	for _, cq := range clusterQueues.Items {
+		// with a namespace only the cluster queues its local queues submit to are relevant
+		if input.Namespace != "" && !usedClusterQueues[cq.GetName()] {
+			continue
+		}
+		pending, _, _ := unstructured.NestedInt64(cq.Object, "status", "pendingWorkloads")
+		admitted, _, _ := unstructured.NestedInt64(cq.Object, "status", "admittedWorkloads")
+		msg += fmt.Sprintf("- %s: %d admitted, %d pending workloads\n", cq.GetName(), admitted, pending)
+		for _, flavor := range clusterQueueFlavorUsage(cq) {
+			msg += fmt.Sprintf("  flavor %s\n", flavor)
+		}
+	}
+	return nil, ListQueuesOutput{Queues: msg}, nil
+}
+
+// Lists Kueue Workloads of a project with their admission state and the reason they are pending
+func ListWorkloads(ctx context.Context, req *mcp.CallToolRequest, input ListWorkbenchesInput) (*mcp.CallToolResult, ListWorkloadsOutput, error) {
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, ListWorkloadsOutput{}, err
+	}
+
+	workloads, err := dyn.Resource(workloadsGVR).Namespace(input.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, ListWorkloadsOutput{}, fmt.Errorf("failed to list workloads: %v", err)
+	}
+	if len(workloads.Items) == 0 {
+		return nil, ListWorkloadsOutput{Workloads: fmt.Sprintf("No workloads found in project %s", input.Namespace)}, nil
+	}
+
+	msg := ""
+	for _, wl := range workloads.Items {
+		queue, _, _ := unstructured.NestedString(wl.Object, "spec", "queueName")
+		msg += fmt.Sprintf("- %s", wl.GetName())
+		for _, owner := range wl.GetOwnerReferences() {
+			msg += fmt.Sprintf(" (%s %s)", owner.Kind, owner.Name)
+		}
+		msg += fmt.Sprintf(" in queue %s: %s\n", queue, workloadState(wl))
+	}
+	return nil, ListWorkloadsOutput{Workloads: msg}, nil
+}
+
+// workloadState describes the admission of a workload, including why it is pending or was evicted
+func workloadState(wl unstructured.Unstructured) string {
+	conditions := map[string]map[string]interface{}{}
+	conditionsRaw, _, _ := unstructured.NestedSlice(wl.Object, "status", "conditions")
+	for _, c := range conditionsRaw {
+		if condition, ok := c.(map[string]interface{}); ok {
+			conditionType, _ := condition["type"].(string)
+			conditions[conditionType] = condition
+		}
+	}
+	isTrue := func(conditionType string) bool {
+		return conditions[conditionType] != nil && conditions[conditionType]["status"] == "True"
+	}
+	message := func(conditionType string) string {
+		m, _ := conditions[conditionType]["message"].(string)
+		return m
+	}
+
+	switch {
+	case isTrue("Finished"):
+		return fmt.Sprintf("finished (%s)", message("Finished"))
+	case isTrue("Evicted"):
+		return fmt.Sprintf("evicted (%s)", message("Evicted"))
+	case isTrue("Admitted"):
+		clusterQueue, _, _ := unstructured.NestedString(wl.Object, "status", "admission", "clusterQueue")
+		return fmt.Sprintf("admitted by cluster queue %s", clusterQueue)
+	case isTrue("QuotaReserved"):
+		return "quota reserved, waiting for admission checks"
+	case conditions["QuotaReserved"] != nil && message("QuotaReserved") != "":
+		return fmt.Sprintf("pending (%s)", message("QuotaReserved"))
+	}
+	return "pending"
+}
+
+// clusterQueueFlavorUsage describes for every flavor of a cluster queue how much of each resource is used out of its nominal quota
+func clusterQueueFlavorUsage(cq unstructured.Unstructured) []string {
+	used := map[string]map[string]string{}
+	usageRaw, _, _ := unstructured.NestedSlice(cq.Object, "status", "flavorsUsage")
+	for _, u := range usageRaw {
+		usage, _ := u.(map[string]interface{})
+		flavor, _ := usage["name"].(string)
+		used[flavor] = map[string]string{}
+		resources, _ := usage["resources"].([]interface{})
+		for _, r := range resources {
+			resource, _ := r.(map[string]interface{})
+			name, _ := resource["name"].(string)
+			used[flavor][name] = fmt.Sprint(resource["total"])
+		}
+	}
+
+	var flavors []string
+	groups, _, _ := unstructured.NestedSlice(cq.Object, "spec", "resourceGroups")
+	for _, g := range groups {
+		group, _ := g.(map[string]interface{})
+		groupFlavors, _ := group["flavors"].([]interface{})
+		for _, f := range groupFlavors {
+			flavor, _ := f.(map[string]interface{})
+			flavorName, _ := flavor["name"].(string)
+			resources, _ := flavor["resources"].([]interface{})
+			var quotas []string
+			for _, r := range resources {
+				resource, _ := r.(map[string]interface{})
+				name, _ := resource["name"].(string)
+				usedQuantity := used[flavorName][name]
+				if usedQuantity == "" {
+					usedQuantity = "0"
+				}
+				quotas = append(quotas, fmt.Sprintf("%s %s/%v", name, usedQuantity, resource["nominalQuota"]))
+			}
+			sort.Strings(quotas)
+			flavors = append(flavors, fmt.Sprintf("%s: %s", flavorName, strings.Join(quotas, ", ")))
+		}
+	}
+	return flavors
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newUnstructuredLocalQueue(name, namespace, clusterQueue string, admitted, pending int64) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec":   map[string]interface{}{"clusterQueue": clusterQueue},
+		"status": map[string]interface{}{"admittedWorkloads": admitted, "pendingWorkloads": pending},
+	}}
+	u.SetGroupVersionKind(localQueuesGVR.GroupVersion().WithKind("LocalQueue"))
+	u.SetName(name)
+	u.SetNamespace(namespace)
+	return u
+}
+
+func newUnstructuredClusterQueue(name string, quota, usage map[string]interface{}) *unstructured.Unstructured {
+	var resources, usedResources []interface{}
+	for resource, nominalQuota := range quota {
+		resources = append(resources, map[string]interface{}{"name": resource, "nominalQuota": nominalQuota})
+	}
+	for resource, total := range usage {
+		usedResources = append(usedResources, map[string]interface{}{"name": resource, "total": total})
+	}
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"resourceGroups": []interface{}{
+				map[string]interface{}{
+					"flavors": []interface{}{
+						map[string]interface{}{"name": "default-flavor", "resources": resources},
+					},
+				},
+			},
+		},
+		"status": map[string]interface{}{
+			"admittedWorkloads": int64(1),
+			"pendingWorkloads":  int64(1),
+			"flavorsUsage": []interface{}{
+				map[string]interface{}{"name": "default-flavor", "resources": usedResources},
+			},
+		},
+	}}
+	u.SetGroupVersionKind(clusterQueuesGVR.GroupVersion().WithKind("ClusterQueue"))
+	u.SetName(name)
+	return u
+}
+
+func newUnstructuredWorkload(name, namespace, queue, ownerKind string, conditions ...map[string]interface{}) *unstructured.Unstructured {
+	var conditionsRaw []interface{}
+	for _, condition := range conditions {
+		conditionsRaw = append(conditionsRaw, condition)
+	}
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec":   map[string]interface{}{"queueName": queue},
+		"status": map[string]interface{}{"conditions": conditionsRaw, "admission": map[string]interface{}{"clusterQueue": "team-a"}},
+	}}
+	u.SetGroupVersionKind(workloadsGVR.GroupVersion().WithKind("Workload"))
+	u.SetName(name)
+	u.SetNamespace(namespace)
+	u.SetOwnerReferences([]metav1.OwnerReference{{Kind: ownerKind, Name: strings.TrimPrefix(name, "wl-")}})
+	return u
+}
+
+func TestListQueues(t *testing.T) {
+	orig := getDynamicClient
+	defer func() { getDynamicClient = orig }()
+
+	scheme := runtime.NewScheme()
+	client := dynamicfake.NewSimpleDynamicClient(scheme,
+		newUnstructuredLocalQueue("local-queue", "ns1", "team-a", 1, 1),
+		newUnstructuredLocalQueue("local-queue", "ns2", "team-b", 0, 0),
+		newUnstructuredClusterQueue("team-a", map[string]interface{}{"cpu": "8", "nvidia.com/gpu": "2"}, map[string]interface{}{"cpu": "4", "nvidia.com/gpu": "2"}),
+		newUnstructuredClusterQueue("team-b", map[string]interface{}{"cpu": "16"}, map[string]interface{}{}),
+	)
+	getDynamicClient = func() (dynamic.Interface, error) {
+		return client, nil
+	}
+	ctx := context.Background()
+
+	_, out, err := ListQueues(ctx, nil, ListQueuesInput{Namespace: "ns1"})
+	if err != nil {
+		t.Fatalf("ListQueues returned error: %v", err)
+	}
+	expected := "Local queues:\n" +
+		"- ns1/local-queue -> cluster queue team-a: 1 admitted, 1 pending workloads\n" +
+		"Cluster queues:\n" +
+		"- team-a: 1 admitted, 1 pending workloads\n" +
+		"  flavor default-flavor: cpu 4/8, nvidia.com/gpu 2/2\n"
+	if out.Queues != expected {
+		t.Errorf("unexpected queues:\n%s", out.Queues)
+	}
+
+	_, out, err = ListQueues(ctx, nil, ListQueuesInput{})
+	if err != nil {
+		t.Fatalf("ListQueues returned error: %v", err)
+	}
+	if !strings.Contains(out.Queues, "- ns2/local-queue -> cluster queue team-b") || !strings.Contains(out.Queues, "  flavor default-flavor: cpu 0/16\n") {
+		t.Errorf("expected queues of all namespaces, got:\n%s", out.Queues)
+	}
+
+	client.PrependReactor("list", "clusterqueues", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.NewForbidden(clusterQueuesGVR.GroupResource(), "", fmt.Errorf("cluster scoped"))
+	})
+	_, out, err = ListQueues(ctx, nil, ListQueuesInput{Namespace: "ns1"})
+	if err != nil {
+		t.Fatalf("ListQueues returned error when cluster queues are forbidden: %v", err)
+	}
+	expected = "Local queues:\n" +
+		"- ns1/local-queue -> cluster queue team-a: 1 admitted, 1 pending workloads\n" +
+		"Cluster queues:\n" +
+		"- quota details are unavailable, listing cluster queues is not permitted\n"
+	if out.Queues != expected {
+		t.Errorf("unexpected queues:\n%s", out.Queues)
+	}
+}
+
+func TestListWorkloads(t *testing.T) {
+	orig := getDynamicClient
+	defer func() { getDynamicClient = orig }()
+
+	scheme := runtime.NewScheme()
+	client := dynamicfake.NewSimpleDynamicClient(scheme,
+		newUnstructuredWorkload("wl-train", "ns1", "local-queue", "PyTorchJob",
+			map[string]interface{}{"type": "QuotaReserved", "status": "True"},
+			map[string]interface{}{"type": "Admitted", "status": "True"},
+		),
+		newUnstructuredWorkload("wl-raycluster", "ns1", "local-queue", "RayCluster",
+			map[string]interface{}{"type": "QuotaReserved", "status": "False", "reason": "Pending", "message": "couldn't assign flavors to pod set workers: insufficient quota for nvidia.com/gpu in flavor default-flavor"},
+		),
+		newUnstructuredWorkload("wl-old", "ns1", "local-queue", "PyTorchJob",
+			map[string]interface{}{"type": "Finished", "status": "True", "message": "Job finished successfully"},
+		),
+	)
+	getDynamicClient = func() (dynamic.Interface, error) {
+		return client, nil
+	}
+
+	_, out, err := ListWorkloads(context.Background(), nil, ListWorkbenchesInput{Namespace: "ns1"})
+	if err != nil {
+		t.Fatalf("ListWorkloads returned error: %v", err)
+	}
+	for _, expected := range []string{
+		"- wl-train (PyTorchJob train) in queue local-queue: admitted by cluster queue team-a\n",
+		"- wl-raycluster (RayCluster raycluster) in queue local-queue: pending (couldn't assign flavors to pod set workers: insufficient quota for nvidia.com/gpu in flavor default-flavor)\n",
+		"- wl-old (PyTorchJob old) in queue local-queue: finished (Job finished successfully)\n",
+	} {
+		if !strings.Contains(out.Workloads, expected) {
+			t.Errorf("expected %q in output, got:\n%s", expected, out.Workloads)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
@@ -35,3 +36,24 @@ func LogIntoClusterDynamic() (*dynamic.DynamicClient, error) {
 	}
 	return dyn, nil
 }
+
+func LogIntoClusterToken() (string, error) {
+	kubeconfigPath := filepath.Join(os.Getenv("HOME"), ".kube", "config")
+	config, err := clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to load kubeconfig: %v", err)
+	}
+	if config.BearerToken == "" {
+		return "", fmt.Errorf("kubeconfig has no bearer token, log in with oc login")
+	}
+	return config.BearerToken, nil
+}
+
+func LogIntoClusterConfig() (*rest.Config, error) {
+	kubeconfigPath := filepath.Join(os.Getenv("HOME"), ".kube", "config")
+	config, err := clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %v", err)
+	}
+	return config, nil
+}
@@ -25,7 +25,7 @@ func main() {
 
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "List All Workbenches",
-		Description: "list the workbenches across all namespaces",
+		Description: "list the workbenches across all namespaces grouped by project with counts per state, optionally only in data science projects",
 	}, ListAllWorkbenches)
 
 	mcp.AddTool(server, &mcp.Tool{
@@ -40,9 +40,289 @@ func main() {
 
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "Create Workbench",
-		Description: "create a new workbench with given name, image and image URL in a given project namespace",
+		Description: "create a new workbench with given name, image and image URL in a given project namespace, optionally using existing storage as home volume, mounting additional storage and injecting connections and environment variables",
 	}, CreateWorkbench)
 
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Update Workbench Environment",
+		Description: "add or remove connections and environment variables of an existing workbench, the workbench restarts if it is running",
+	}, UpdateWorkbenchEnvironment)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "List Projects",
+		Description: "list the data science projects (namespaces labelled opendatahub.io/dashboard=true) with their display names and descriptions",
+	}, ListProjects)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Create Project",
+		Description: "create a new data science project with given name, display name and description",
+	}, CreateProject)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Delete Project",
+		Description: "delete the data science project with given name together with everything in it",
+	}, DeleteProject)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "List Project Permissions",
+		Description: "list the users and groups with admin, edit or view access to a given project namespace",
+	}, ListPermissions)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Grant Project Permission",
+		Description: "give a user or group admin, edit or view access to a given project namespace",
+	}, GrantPermission)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Revoke Project Permission",
+		Description: "remove the access of a user or group to a given project namespace",
+	}, RevokePermission)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "List Storage",
+		Description: "list the cluster storage (persistent volume claims) in a given project namespace with size, storage class, state and the workbenches using it",
+	}, ListStorage)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Create Storage",
+		Description: "create a new cluster storage with given name, size and optional storage class in a given project namespace",
+	}, CreateStorage)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Expand Storage",
+		Description: "expand a cluster storage to a bigger size if its storage class allows volume expansion",
+	}, ExpandStorage)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Delete Storage",
+		Description: "delete a cluster storage which is not attached to any workbench",
+	}, DeleteStorage)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Attach Storage",
+		Description: "mount an existing cluster storage into a workbench at a given path, the workbench restarts if it is running",
+	}, AttachStorage)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Detach Storage",
+		Description: "unmount a cluster storage from a workbench, the storage itself is kept",
+	}, DetachStorage)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "List Connections",
+		Description: "list the connections (data connections) in a given project namespace with their type, secret values are never returned",
+	}, ListConnections)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Create Connection",
+		Description: "create a new s3, uri or oci registry connection in a given project namespace",
+	}, CreateConnection)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Delete Connection",
+		Description: "delete a connection in a given project namespace",
+	}, DeleteConnection)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Test Connection",
+		Description: "check the credentials of an s3 connection by listing its bucket on its endpoint and report why it fails",
+	}, CheckConnection)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "List Model Deployments",
+		Description: "list the deployed models (KServe InferenceServices) in a given project namespace or all namespaces with model format, runtime, readiness, replicas, URLs and model source",
+	}, ListModelDeployments)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Deploy Model",
+		Description: "deploy a model with KServe in a given project namespace using a serving runtime template (vLLM, OVMS, ...) and a connection with path or an OCI URI",
+	}, DeployModel)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Scale Model Deployment",
+		Description: "change the minimum and maximum number of replicas of a deployed model",
+	}, ScaleModelDeployment)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Change Model Deployment Status",
+		Description: "start or stop a deployed model with given name in a given project namespace",
+	}, ChangeModelDeploymentStatus)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Delete Model Deployment",
+		Description: "delete a deployed model together with its serving runtime and token auth service account",
+	}, DeleteModelDeployment)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Run Inference",
+		Description: "send an OpenAI compatible chat or completions request or a KServe v2 inference request to a deployed model and return its response and latency",
+	}, RunInference)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "List Registered Models",
+		Description: "list the registered models in a model registry",
+	}, ListRegisteredModels)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Get Registered Model",
+		Description: "list the versions of a registered model with their artifacts, URIs and metadata",
+	}, GetRegisteredModel)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Register Model Version",
+		Description: "register a new version of a model stored under a path of an s3 connection, the registered model is created if needed",
+	}, RegisterModelVersion)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Deploy Registered Model",
+		Description: "deploy a registered model version with KServe in a given project namespace",
+	}, DeployRegisteredModel)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "List Serving Runtimes",
+		Description: "list the serving runtime templates available for model deployment with supported model formats, API protocols and accelerators",
+	}, ListServingRuntimes)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Get Pipeline Server",
+		Description: "check whether a given project namespace has a pipeline server and report the readiness of its components",
+	}, GetPipelineServer)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Create Pipeline Server",
+		Description: "create a pipeline server in a given project namespace storing pipeline artifacts through an existing s3 connection",
+	}, CreatePipelineServer)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "List Pipelines",
+		Description: "list pipelines and their versions on the pipeline server of a given project namespace",
+	}, ListPipelines)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Start Pipeline Run",
+		Description: "start a run of a pipeline with the given parameters, the newest pipeline version is used when no version is given",
+	}, StartPipelineRun)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "List Pipeline Runs",
+		Description: "list pipeline runs with their state on the pipeline server of a given project namespace",
+	}, ListPipelineRuns)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Get Pipeline Run",
+		Description: "get the tasks of a pipeline run and the step which made it fail",
+	}, GetPipelineRun)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Terminate Pipeline Run",
+		Description: "terminate a running pipeline run",
+	}, TerminatePipelineRun)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Retry Pipeline Run",
+		Description: "retry a failed or terminated pipeline run",
+	}, RetryPipelineRun)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Upload Pipeline",
+		Description: "validate a compiled KFP IR YAML, given inline or as a path in a workbench, and upload it as a new pipeline or a new version of an existing one",
+	}, UploadPipeline)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Create Recurring Run",
+		Description: "create a recurring run of a pipeline triggered by a cron expression or an interval in seconds",
+	}, CreateRecurringRun)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "List Recurring Runs",
+		Description: "list recurring runs with their schedule and status on the pipeline server of a given project namespace",
+	}, ListRecurringRuns)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Pause Recurring Run",
+		Description: "pause a recurring run so it stops starting new runs",
+	}, PauseRecurringRun)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Resume Recurring Run",
+		Description: "resume a paused recurring run",
+	}, ResumeRecurringRun)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Delete Recurring Run",
+		Description: "delete a recurring run, runs it already started are kept",
+	}, DeleteRecurringRun)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "List Queues",
+		Description: "list Kueue local queues of a given namespace, or all namespaces, and the cluster queues behind them with quota usage per resource flavor",
+	}, ListQueues)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "List Workloads",
+		Description: "list Kueue workloads in a given project namespace with their admission state and the reason they are pending",
+	}, ListWorkloads)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "List Ray Clusters",
+		Description: "list ray clusters in a given project namespace with their state, worker readiness and dashboard URL",
+	}, ListRayClusters)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Create Ray Cluster",
+		Description: "create a ray cluster with a head and a worker group of given size and resources, optionally submitted to a Kueue local queue",
+	}, CreateRayCluster)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Scale Ray Cluster",
+		Description: "scale a worker group of a ray cluster to a given number of workers",
+	}, ScaleRayCluster)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Delete Ray Cluster",
+		Description: "delete a ray cluster",
+	}, DeleteRayCluster)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Create Training Job",
+		Description: "submit a PyTorchJob with a given image, command, number of workers, GPUs and optionally a mounted storage, connection and Kueue local queue",
+	}, CreateTrainingJob)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Get Training Job",
+		Description: "get the state of a PyTorchJob and of its master and worker replicas",
+	}, GetTrainingJob)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Get Training Job Logs",
+		Description: "get the logs of a PyTorchJob replica by its rank, the master has rank 0",
+	}, GetTrainingJobLogs)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Delete Training Job",
+		Description: "delete a PyTorchJob",
+	}, DeleteTrainingJob)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Find Images",
+		Description: "find notebook images whose tags provide the requested packages or capabilities, f.e. pytorch>=2.3, python 3.12, cuda, returning the image display name and tag pairs for creating a workbench",
+	}, FindImages)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Import Notebook Image",
+		Description: "import a custom notebook image with a display name, description and declared software and packages so it can be used for workbenches",
+	}, ImportNotebookImage)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Change Notebook Image Status",
+		Description: "enable or disable a custom notebook image, disabled images are not offered for new workbenches",
+	}, ChangeNotebookImageStatus)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "Delete Notebook Image",
+		Description: "delete a custom notebook image",
+	}, DeleteNotebookImage)
+
 	server.AddResource(&mcp.Resource{
 		URI:         "resource://mcp-test/images",
 		Name:        "Image Catalog",
@@ -50,6 +330,13 @@ func main() {
 		MIMEType:    "application/json",
 	}, ImagesResourceHandler)
 
+	server.AddResource(&mcp.Resource{
+		URI:         "resource://mcp-test/serving-runtimes",
+		Name:        "Serving Runtime Catalog",
+		Description: "List of available serving runtime templates with supported model formats, API protocols and accelerators",
+		MIMEType:    "application/json",
+	}, ServingRuntimesResourceHandler)
+
 	server.AddPrompt(&mcp.Prompt{
 		Name:        "create-workbench",
 		Description: "Guide to create a workbench",
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const modelRegistryAPIPath = "/api/model_registry/v1alpha3"
+
+const modelRegistryPageSize = 100
+
+// modelRegistryClient talks to the REST API of a Kubeflow Model Registry
+type modelRegistryClient struct {
+	baseURL string
+	token   string
+}
+
+type registeredModel struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	State       string `json:"state,omitempty"`
+	Owner       string `json:"owner,omitempty"`
+}
+
+type modelVersion struct {
+	ID                string `json:"id,omitempty"`
+	Name              string `json:"name"`
+	RegisteredModelID string `json:"registeredModelId"`
+	Description       string `json:"description,omitempty"`
+	Author            string `json:"author,omitempty"`
+	State             string `json:"state,omitempty"`
+}
+
+type modelArtifact struct {
+	ID                 string                 `json:"id,omitempty"`
+	ArtifactType       string                 `json:"artifactType"`
+	Name               string                 `json:"name"`
+	URI                string                 `json:"uri"`
+	Description        string                 `json:"description,omitempty"`
+	ModelFormatName    string                 `json:"modelFormatName,omitempty"`
+	ModelFormatVersion string                 `json:"modelFormatVersion,omitempty"`
+	StorageKey         string                 `json:"storageKey,omitempty"`
+	StoragePath        string                 `json:"storagePath,omitempty"`
+	CustomProperties   map[string]interface{} `json:"customProperties,omitempty"`
+}
+
+// newModelRegistryClient creates a client for the registry at registryURL, falling back to
+// the MODEL_REGISTRY_URL environment variable. Credentials are only sent to the configured
+// registry: MODEL_REGISTRY_TOKEN or the kubeconfig token is the bearer token there, any other
+// URL is called without a token.
+func newModelRegistryClient(registryURL string) (*modelRegistryClient, error) {
+	configuredURL := strings.TrimSuffix(os.Getenv("MODEL_REGISTRY_URL"), "/")
+	registryURL = strings.TrimSuffix(registryURL, "/")
+	if registryURL == "" {
+		registryURL = configuredURL
+	}
+	if registryURL == "" {
+		return nil, fmt.Errorf("model registry URL is not set, pass it or set MODEL_REGISTRY_URL")
+	}
+
+	token := ""
+	if registryURL == configuredURL {
+		token = os.Getenv("MODEL_REGISTRY_TOKEN")
+		if token == "" {
+			// registries without auth (f.e. port forwarded ones) do not need a token
+			token, _ = getClusterToken()
+		}
+	}
+
+	return &modelRegistryClient{
+		baseURL: registryURL + modelRegistryAPIPath,
+		token:   token,
+	}, nil
+}
+
+func (c *modelRegistryClient) listRegisteredModels(ctx context.Context) ([]registeredModel, error) {
+	return listModelRegistryItems[registeredModel](ctx, c, "/registered_models")
+}
+
+// listModelRegistryItems fetches every page of a model registry list endpoint
+func listModelRegistryItems[T any](ctx context.Context, c *modelRegistryClient, path string) ([]T, error) {
+	var items []T
+	pageToken := ""
+	for {
+		query := url.Values{}
+		query.Set("pageSize", fmt.Sprintf("%d", modelRegistryPageSize))
+		if pageToken != "" {
+			query.Set("nextPageToken", pageToken)
+		}
+		var page struct {
+			Items         []T    `json:"items"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		if err := doJSONRequest(ctx, http.MethodGet, c.baseURL+path+"?"+query.Encode(), c.token, nil, &page); err != nil {
+			return nil, err
+		}
+		items = append(items, page.Items...)
+		// the registry hands out a token for the page after the last one too
+		if page.NextPageToken == "" || page.NextPageToken == pageToken || len(page.Items) == 0 {
+			return items, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+// findRegisteredModel returns the registered model with the given name, nil when there is none
+func (c *modelRegistryClient) findRegisteredModel(ctx context.Context, name string) (*registeredModel, error) {
+	models, err := c.listRegisteredModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, model := range models {
+		if model.Name == name {
+			return &model, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *modelRegistryClient) listModelVersions(ctx context.Context, modelID string) ([]modelVersion, error) {
+	return listModelRegistryItems[modelVersion](ctx, c, fmt.Sprintf("/registered_models/%s/versions", url.PathEscape(modelID)))
+}
+
+func (c *modelRegistryClient) listModelArtifacts(ctx context.Context, versionID string) ([]modelArtifact, error) {
+	return listModelRegistryItems[modelArtifact](ctx, c, fmt.Sprintf("/model_versions/%s/artifacts", url.PathEscape(versionID)))
+}
+
+func (c *modelRegistryClient) createRegisteredModel(ctx context.Context, model registeredModel) (*registeredModel, error) {
+	var created registeredModel
+	if err := doJSONRequest(ctx, http.MethodPost, c.baseURL+"/registered_models", c.token, model, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (c *modelRegistryClient) createModelVersion(ctx context.Context, version modelVersion) (*modelVersion, error) {
+	var created modelVersion
+	if err := doJSONRequest(ctx, http.MethodPost, c.baseURL+"/model_versions", c.token, version, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (c *modelRegistryClient) createModelArtifact(ctx context.Context, versionID string, artifact modelArtifact) (*modelArtifact, error) {
+	var created modelArtifact
+	if err := doJSONRequest(ctx, http.MethodPost, fmt.Sprintf("%s/model_versions/%s/artifacts", c.baseURL, url.PathEscape(versionID)), c.token, artifact, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func ListRegisteredModels(ctx context.Context, req *mcp.CallToolRequest, input ModelRegistryInput) (*mcp.CallToolResult, ListRegisteredModelsOutput, error) {
+	client, err := newModelRegistryClient(input.RegistryURL)
+	if err != nil {
+		return nil, ListRegisteredModelsOutput{}, err
+	}
+
+	models, err := client.listRegisteredModels(ctx)
+	if err != nil {
+		return nil, ListRegisteredModelsOutput{}, fmt.Errorf("failed to list registered models: %v", err)
+	}
+
+	msg := ""
+	for _, model := range models {
+		msg += fmt.Sprintf("- %s (id %s, %s)", model.Name, model.ID, valueOrUnknown(model.State))
+		if model.Description != "" {
+			msg += fmt.Sprintf(": %s", model.Description)
+		}
+		msg += "\n"
+	}
+	return nil, ListRegisteredModelsOutput{Models: msg}, nil
+}
+
+// Lists the versions of a registered model together with their artifacts
+func GetRegisteredModel(ctx context.Context, req *mcp.CallToolRequest, input GetRegisteredModelInput) (*mcp.CallToolResult, GetRegisteredModelOutput, error) {
+	client, err := newModelRegistryClient(input.RegistryURL)
+	if err != nil {
+		return nil, GetRegisteredModelOutput{}, err
+	}
+
+	model, err := client.findRegisteredModel(ctx, input.ModelName)
+	if err != nil {
+		return nil, GetRegisteredModelOutput{}, fmt.Errorf("failed to get registered model %s: %v", input.ModelName, err)
+	}
+	if model == nil {
+		return nil, GetRegisteredModelOutput{}, fmt.Errorf("registered model not found: %s", input.ModelName)
+	}
+
+	versions, err := client.listModelVersions(ctx, model.ID)
+	if err != nil {
+		return nil, GetRegisteredModelOutput{}, fmt.Errorf("failed to list versions of %s: %v", input.ModelName, err)
+	}
+
+	msg := ""
+	for _, version := range versions {
+		msg += fmt.Sprintf("Version: %s (id %s, %s)\n", version.Name, version.ID, valueOrUnknown(version.State))
+		if version.Author != "" {
+			msg += fmt.Sprintf(" Author: %s\n", version.Author)
+		}
+		if version.Description != "" {
+			msg += fmt.Sprintf(" Description: %s\n", version.Description)
+		}
+
+		artifacts, err := client.listModelArtifacts(ctx, version.ID)
+		if err != nil {
+			return nil, GetRegisteredModelOutput{}, fmt.Errorf("failed to list artifacts of version %s: %v", version.Name, err)
+		}
+		for _, artifact := range artifacts {
+			format := strings.TrimSpace(artifact.ModelFormatName + " " + artifact.ModelFormatVersion)
+			msg += fmt.Sprintf(" Artifact: %s\n  URI: %s\n  Format: %s\n", artifact.Name, artifact.URI, valueOrUnknown(format))
+			if artifact.StorageKey != "" {
+				msg += fmt.Sprintf("  Connection: %s, path %s\n", artifact.StorageKey, artifact.StoragePath)
+			}
+			if len(artifact.CustomProperties) > 0 {
+				properties, _ := json.Marshal(artifact.CustomProperties)
+				msg += fmt.Sprintf("  Metadata: %s\n", properties)
+			}
+		}
+	}
+	return nil, GetRegisteredModelOutput{Versions: msg}, nil
+}
+
+// Registers a model stored under a path of an s3 connection as a new version
+func RegisterModelVersion(ctx context.Context, req *mcp.CallToolRequest, input RegisterModelVersionInput) (*mcp.CallToolResult, ModelRegistryOutput, error) {
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, ModelRegistryOutput{}, err
+	}
+
+	secret, err := getConnection(ctx, dyn, input.Namespace, input.ConnectionName)
+	if err != nil {
+		return nil, ModelRegistryOutput{}, err
+	}
+	if connectionType(*secret) != "s3" {
+		return nil, ModelRegistryOutput{}, fmt.Errorf("connection %s is not an s3 connection", input.ConnectionName)
+	}
+	values, err := connectionValues(*secret)
+	if err != nil {
+		return nil, ModelRegistryOutput{}, err
+	}
+
+	// the same URI format the dashboard uses when registering from object storage
+	query := url.Values{}
+	query.Set("endpoint", values["AWS_S3_ENDPOINT"])
+	if values["AWS_DEFAULT_REGION"] != "" {
+		query.Set("defaultRegion", values["AWS_DEFAULT_REGION"])
+	}
+	uri := fmt.Sprintf("s3://%s/%s?%s", values["AWS_S3_BUCKET"], strings.TrimPrefix(input.ModelPath, "/"), query.Encode())
+
+	client, err := newModelRegistryClient(input.RegistryURL)
+	if err != nil {
+		return nil, ModelRegistryOutput{}, err
+	}
+
+	model, err := client.findRegisteredModel(ctx, input.ModelName)
+	if err != nil {
+		return nil, ModelRegistryOutput{}, fmt.Errorf("failed to get registered model %s: %v", input.ModelName, err)
+	}
+	if model == nil {
+		model, err = client.createRegisteredModel(ctx, registeredModel{Name: input.ModelName})
+		if err != nil {
+			return nil, ModelRegistryOutput{}, fmt.Errorf("failed to register model %s: %v", input.ModelName, err)
+		}
+	}
+
+	version, err := client.createModelVersion(ctx, modelVersion{
+		Name:              input.VersionName,
+		RegisteredModelID: model.ID,
+		Author:            input.Author,
+		Description:       input.Description,
+	})
+	if err != nil {
+		return nil, ModelRegistryOutput{}, fmt.Errorf("failed to create version %s of %s: %v", input.VersionName, input.ModelName, err)
+	}
+
+	_, err = client.createModelArtifact(ctx, version.ID, modelArtifact{
+		ArtifactType:       "model-artifact",
+		Name:               input.ModelName,
+		URI:                uri,
+		ModelFormatName:    input.ModelFormatName,
+		ModelFormatVersion: input.ModelFormatVersion,
+		StorageKey:         input.ConnectionName,
+		StoragePath:        input.ModelPath,
+	})
+	if err != nil {
+		return nil, ModelRegistryOutput{}, fmt.Errorf("failed to create artifact of version %s: %v", input.VersionName, err)
+	}
+
+	return nil, ModelRegistryOutput{Message: fmt.Sprintf("Version %s of model %s was registered with URI %s (model id %s, version id %s)", input.VersionName, input.ModelName, uri, model.ID, version.ID)}, nil
+}
+
+// Deploys the artifact of a registered model version with KServe
+func DeployRegisteredModel(ctx context.Context, req *mcp.CallToolRequest, input DeployRegisteredModelInput) (*mcp.CallToolResult, ModelDeploymentOutput, error) {
+	client, err := newModelRegistryClient(input.RegistryURL)
+	if err != nil {
+		return nil, ModelDeploymentOutput{}, err
+	}
+
+	model, err := client.findRegisteredModel(ctx, input.ModelName)
+	if err != nil {
+		return nil, ModelDeploymentOutput{}, fmt.Errorf("failed to get registered model %s: %v", input.ModelName, err)
+	}
+	if model == nil {
+		return nil, ModelDeploymentOutput{}, fmt.Errorf("registered model not found: %s", input.ModelName)
+	}
+
+	versions, err := client.listModelVersions(ctx, model.ID)
+	if err != nil {
+		return nil, ModelDeploymentOutput{}, fmt.Errorf("failed to list versions of %s: %v", input.ModelName, err)
+	}
+	var version *modelVersion
+	for _, v := range versions {
+		if v.Name == input.VersionName {
+			version = &v
+			break
+		}
+	}
+	if version == nil {
+		return nil, ModelDeploymentOutput{}, fmt.Errorf("version %s of model %s not found", input.VersionName, input.ModelName)
+	}
+
+	artifacts, err := client.listModelArtifacts(ctx, version.ID)
+	if err != nil {
+		return nil, ModelDeploymentOutput{}, fmt.Errorf("failed to list artifacts of version %s: %v", input.VersionName, err)
+	}
+	var artifact *modelArtifact
+	for _, a := range artifacts {
+		if a.ArtifactType == "" || a.ArtifactType == "model-artifact" {
+			artifact = &a
+			break
+		}
+	}
+	if artifact == nil {
+		return nil, ModelDeploymentOutput{}, fmt.Errorf("version %s of model %s has no model artifact", input.VersionName, input.ModelName)
+	}
+
+	deployInput := DeployModelInput{
+		Namespace:          input.Namespace,
+		ModelName:          input.DeploymentName,
+		DisplayName:        fmt.Sprintf("%s %s", input.ModelName, input.VersionName),
+		RuntimeTemplate:    input.RuntimeTemplate,
+		ModelFormat:        artifact.ModelFormatName,
+		Replicas:           input.Replicas,
+		CPU:                input.CPU,
+		Memory:             input.Memory,
+		Accelerator:        input.Accelerator,
+		AcceleratorCount:   input.AcceleratorCount,
+		AcceleratorProfile: input.AcceleratorProfile,
+		ExternalRoute:      input.ExternalRoute,
+		TokenAuth:          input.TokenAuth,
+	}
+	if strings.HasPrefix(artifact.URI, "s3://") {
+		deployInput.ConnectionName = input.ConnectionName
+		if deployInput.ConnectionName == "" {
+			deployInput.ConnectionName = artifact.StorageKey
+		}
+		if deployInput.ConnectionName == "" {
+			return nil, ModelDeploymentOutput{}, fmt.Errorf("artifact %s is stored in s3, a connection name is required", artifact.URI)
+		}
+		deployInput.ModelPath = artifact.StoragePath
+		if deployInput.ModelPath == "" {
+			s3URI, err := url.Parse(artifact.URI)
+			if err != nil {
+				return nil, ModelDeploymentOutput{}, fmt.Errorf("invalid artifact URI %s: %v", artifact.URI, err)
+			}
+			deployInput.ModelPath = strings.TrimPrefix(s3URI.Path, "/")
+		}
+	} else {
+		deployInput.StorageURI = artifact.URI
+	}
+
+	// link the deployment back to the registry the way the dashboard does
+	out, err := deployModel(ctx, deployInput, map[string]string{
+		"modelregistry.opendatahub.io/registered-model-id": model.ID,
+		"modelregistry.opendatahub.io/model-version-id":    version.ID,
+	})
+	if err != nil {
+		return nil, ModelDeploymentOutput{}, err
+	}
+
+	return nil, out, nil
+}
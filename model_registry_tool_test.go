@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// fakeModelRegistry stands in for the model registry REST API keeping everything in memory
+type fakeModelRegistry struct {
+	mu        sync.Mutex
+	models    []registeredModel
+	versions  []modelVersion
+	artifacts map[string][]modelArtifact
+}
+
+func (f *fakeModelRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, modelRegistryAPIPath)
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case r.Method == http.MethodGet && path == "/registered_models":
+		writeRegistryPage(w, r, f.models)
+	case r.Method == http.MethodPost && path == "/registered_models":
+		var model registeredModel
+		_ = json.NewDecoder(r.Body).Decode(&model)
+		model.ID = fmt.Sprintf("%d", len(f.models)+1)
+		model.State = "LIVE"
+		f.models = append(f.models, model)
+		_ = json.NewEncoder(w).Encode(model)
+	case r.Method == http.MethodGet && len(parts) == 3 && parts[0] == "registered_models" && parts[2] == "versions":
+		var items []modelVersion
+		for _, version := range f.versions {
+			if version.RegisteredModelID == parts[1] {
+				items = append(items, version)
+			}
+		}
+		writeRegistryPage(w, r, items)
+	case r.Method == http.MethodPost && path == "/model_versions":
+		var version modelVersion
+		_ = json.NewDecoder(r.Body).Decode(&version)
+		version.ID = fmt.Sprintf("%d", len(f.versions)+10)
+		version.State = "LIVE"
+		f.versions = append(f.versions, version)
+		_ = json.NewEncoder(w).Encode(version)
+	case len(parts) == 3 && parts[0] == "model_versions" && parts[2] == "artifacts":
+		if r.Method == http.MethodPost {
+			var artifact modelArtifact
+			_ = json.NewDecoder(r.Body).Decode(&artifact)
+			artifact.ID = fmt.Sprintf("a%s", parts[1])
+			f.artifacts[parts[1]] = append(f.artifacts[parts[1]], artifact)
+			_ = json.NewEncoder(w).Encode(artifact)
+			return
+		}
+		writeRegistryPage(w, r, f.artifacts[parts[1]])
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// writeRegistryPage serves items one per page so clients have to follow nextPageToken,
+// like the registry it also returns a token after the last page
+func writeRegistryPage[T any](w http.ResponseWriter, r *http.Request, items []T) {
+	offset, _ := strconv.Atoi(r.URL.Query().Get("nextPageToken"))
+	page := []T{}
+	if offset < len(items) {
+		page = items[offset : offset+1]
+	}
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": page, "nextPageToken": strconv.Itoa(offset + 1)})
+}
+
+func TestModelRegistry(t *testing.T) {
+	origDynamic := getDynamicClient
+	origToken := getClusterToken
+	defer func() {
+		getDynamicClient = origDynamic
+		getClusterToken = origToken
+	}()
+	getClusterToken = func() (string, error) { return "", fmt.Errorf("no kubeconfig") }
+
+	registry := httptest.NewServer(&fakeModelRegistry{artifacts: map[string][]modelArtifact{}})
+	defer registry.Close()
+
+	scheme := runtime.NewScheme()
+	client := dynamicfake.NewSimpleDynamicClient(scheme,
+		newUnstructuredConnection("models", "ns1", "s3", map[string]string{
+			"AWS_S3_ENDPOINT":    "https://minio.example.com",
+			"AWS_S3_BUCKET":      "models",
+			"AWS_DEFAULT_REGION": "us-east-1",
+		}),
+		newUnstructuredServingRuntimeTemplate("vllm-runtime-template", "vLLM ServingRuntime for KServe", "vLLM"),
+	)
+	getDynamicClient = func() (dynamic.Interface, error) {
+		return client, nil
+	}
+	ctx := context.Background()
+
+	if _, _, err := ListRegisteredModels(ctx, nil, ModelRegistryInput{}); err == nil {
+		t.Errorf("expected error without registry URL")
+	}
+
+	_, out, err := RegisterModelVersion(ctx, nil, RegisterModelVersionInput{
+		RegistryURL:     registry.URL,
+		ModelName:       "granite",
+		VersionName:     "v1",
+		Namespace:       "ns1",
+		ConnectionName:  "models",
+		ModelPath:       "granite/v1",
+		ModelFormatName: "vLLM",
+	})
+	if err != nil {
+		t.Fatalf("RegisterModelVersion returned error: %v", err)
+	}
+	expectedURI := "s3://models/granite/v1?defaultRegion=us-east-1&endpoint=https%3A%2F%2Fminio.example.com"
+	if out.Message != fmt.Sprintf("Version v1 of model granite was registered with URI %s (model id 1, version id 10)", expectedURI) {
+		t.Errorf("unexpected message: %q", out.Message)
+	}
+
+	// the fake registry serves one item per page, the second model is only found on page two
+	if _, _, err := RegisterModelVersion(ctx, nil, RegisterModelVersionInput{
+		RegistryURL:    registry.URL,
+		ModelName:      "mistral",
+		VersionName:    "v1",
+		Namespace:      "ns1",
+		ConnectionName: "models",
+		ModelPath:      "mistral/v1",
+	}); err != nil {
+		t.Fatalf("RegisterModelVersion returned error: %v", err)
+	}
+	_, list, err := ListRegisteredModels(ctx, nil, ModelRegistryInput{RegistryURL: registry.URL})
+	if err != nil {
+		t.Fatalf("ListRegisteredModels returned error: %v", err)
+	}
+	if list.Models != "- granite (id 1, LIVE)\n- mistral (id 2, LIVE)\n" {
+		t.Errorf("unexpected models: %q", list.Models)
+	}
+
+	_, versions, err := GetRegisteredModel(ctx, nil, GetRegisteredModelInput{RegistryURL: registry.URL, ModelName: "granite"})
+	if err != nil {
+		t.Fatalf("GetRegisteredModel returned error: %v", err)
+	}
+	if !strings.Contains(versions.Versions, "Version: v1 (id 10, LIVE)\n") || !strings.Contains(versions.Versions, "  URI: "+expectedURI+"\n") {
+		t.Errorf("unexpected versions: %q", versions.Versions)
+	}
+
+	// the registry labels are set on create, a failing patch must not matter
+	client.PrependReactor("patch", "inferenceservices", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, fmt.Errorf("patch denied")
+	})
+	_, deployed, err := DeployRegisteredModel(ctx, nil, DeployRegisteredModelInput{
+		RegistryURL:     registry.URL,
+		ModelName:       "granite",
+		VersionName:     "v1",
+		Namespace:       "ns1",
+		DeploymentName:  "granite-v1",
+		RuntimeTemplate: "vllm-runtime-template",
+	})
+	if err != nil {
+		t.Fatalf("DeployRegisteredModel returned error: %v", err)
+	}
+	if deployed.Message != "Model granite-v1 was succesfully deployed with runtime vllm-runtime-template!" {
+		t.Errorf("unexpected message: %q", deployed.Message)
+	}
+	isvc, err := client.Resource(inferenceServicesGVR).Namespace("ns1").Get(ctx, "granite-v1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected inference service to be created: %v", err)
+	}
+	if source := inferenceServiceSource(*isvc); source != "connection models, path granite/v1" {
+		t.Errorf("unexpected model source: %q", source)
+	}
+	if isvc.GetLabels()["modelregistry.opendatahub.io/model-version-id"] != "10" {
+		t.Errorf("expected model version label, got: %v", isvc.GetLabels())
+	}
+}
+
+func TestModelRegistryClientToken(t *testing.T) {
+	origToken := getClusterToken
+	defer func() { getClusterToken = origToken }()
+	getClusterToken = func() (string, error) { return "kube-token", nil }
+
+	t.Setenv("MODEL_REGISTRY_URL", "https://registry.example.com/")
+	t.Setenv("MODEL_REGISTRY_TOKEN", "")
+
+	for registryURL, expected := range map[string]string{
+		"":                             "kube-token",
+		"https://registry.example.com": "kube-token",
+		"https://attacker.example.com": "",
+	} {
+		client, err := newModelRegistryClient(registryURL)
+		if err != nil {
+			t.Fatalf("newModelRegistryClient returned error: %v", err)
+		}
+		if client.token != expected {
+			t.Errorf("expected token %q for registry URL %q, got %q", expected, registryURL, client.token)
+		}
+	}
+
+	t.Setenv("MODEL_REGISTRY_TOKEN", "registry-token")
+	if client, _ := newModelRegistryClient(""); client.token != "registry-token" {
+		t.Errorf("expected MODEL_REGISTRY_TOKEN for the configured registry, got %q", client.token)
+	}
+	if client, _ := newModelRegistryClient("https://attacker.example.com"); client.token != "" {
+		t.Errorf("expected no token for a registry which is not configured, got %q", client.token)
+	}
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+)
+
+// Lists KServe InferenceServices with their model, runtime, readiness and where they load the model from
+func ListModelDeployments(ctx context.Context, req *mcp.CallToolRequest, input ListModelDeploymentsInput) (*mcp.CallToolResult, ListModelDeploymentsOutput, error) {
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, ListModelDeploymentsOutput{}, err
+	}
+
+	services, err := dyn.Resource(inferenceServicesGVR).Namespace(input.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, ListModelDeploymentsOutput{}, fmt.Errorf("failed to list model deployments: %v", err)
+	}
+
+	msg := ""
+	for _, isvc := range services.Items {
+		msg += fmt.Sprintf("- %s", isvc.GetName())
+		if displayName := isvc.GetAnnotations()["openshift.io/display-name"]; displayName != "" && displayName != isvc.GetName() {
+			msg += fmt.Sprintf(" (%s)", displayName)
+		}
+		if input.Namespace == "" {
+			msg += fmt.Sprintf(" in %s", isvc.GetNamespace())
+		}
+		msg += fmt.Sprintf(": %s\n", inferenceServiceState(isvc))
+
+		format, _, _ := unstructured.NestedString(isvc.Object, "spec", "predictor", "model", "modelFormat", "name")
+		if version, _, _ := unstructured.NestedString(isvc.Object, "spec", "predictor", "model", "modelFormat", "version"); version != "" {
+			format += " " + version
+		}
+		runtime, _, _ := unstructured.NestedString(isvc.Object, "spec", "predictor", "model", "runtime")
+		msg += fmt.Sprintf("  Model format: %s, runtime: %s\n", valueOrUnknown(format), valueOrUnknown(runtime))
+
+		minReplicas, minFound, _ := unstructured.NestedInt64(isvc.Object, "spec", "predictor", "minReplicas")
+		if !minFound {
+			minReplicas = 1
+		}
+		maxReplicas, maxFound, _ := unstructured.NestedInt64(isvc.Object, "spec", "predictor", "maxReplicas")
+		if !maxFound {
+			maxReplicas = minReplicas
+		}
+		msg += fmt.Sprintf("  Replicas: min %d, max %d\n", minReplicas, maxReplicas)
+
+		externalURL, _, _ := unstructured.NestedString(isvc.Object, "status", "url")
+		internalURL, _, _ := unstructured.NestedString(isvc.Object, "status", "address", "url")
+		if inferenceServiceExposed(isvc) && externalURL != "" {
+			msg += fmt.Sprintf("  External URL: %s\n", externalURL)
+		}
+		if internalURL != "" {
+			msg += fmt.Sprintf("  Internal URL: %s\n", internalURL)
+		}
+
+		msg += fmt.Sprintf("  Source: %s\n", inferenceServiceSource(isvc))
+	}
+	return nil, ListModelDeploymentsOutput{Deployments: msg}, nil
+}
+
+// Lists the serving runtime templates offered by the platform
+func ListServingRuntimes(ctx context.Context, req *mcp.CallToolRequest, input ListServingRuntimesInput) (*mcp.CallToolResult, ListServingRuntimesOutput, error) {
+	runtimes, err := GetServingRuntimes(ctx)
+	if err != nil {
+		return nil, ListServingRuntimesOutput{}, err
+	}
+
+	msg := ""
+	for _, runtime := range runtimes {
+		msg += fmt.Sprintf("Runtime: %s\n Template: %s\n Model formats: %s\n API protocol: %s\n Serving platforms: %s\n Accelerators: %s\n",
+			runtime.DisplayName,
+			runtime.Name,
+			strings.Join(runtime.ModelFormats, ", "),
+			valueOrUnknown(runtime.APIProtocol),
+			strings.Join(runtime.ServingPlatforms, ", "),
+			strings.Join(runtime.Accelerators, ", "),
+		)
+	}
+	return nil, ListServingRuntimesOutput{Runtimes: msg}, nil
+}
+
+// inferenceServiceState summarizes the Ready condition of an InferenceService
+func inferenceServiceState(isvc unstructured.Unstructured) string {
+	if isvc.GetAnnotations()["serving.kserve.io/stop"] == "true" {
+		return "stopped"
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(isvc.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["type"] != "Ready" {
+			continue
+		}
+		if condition["status"] == "True" {
+			return "ready"
+		}
+		reason, _ := condition["reason"].(string)
+		message, _ := condition["message"].(string)
+		if message != "" {
+			return fmt.Sprintf("not ready (%s: %s)", valueOrUnknown(reason), message)
+		}
+		return fmt.Sprintf("not ready (%s)", valueOrUnknown(reason))
+	}
+	return "pending"
+}
+
+// inferenceServiceSource describes the connection and path or URI the model is loaded from
+// inferenceServiceExposed tells whether the model is reachable from outside the cluster, raw deployments
+// are exposed by a label while serverless ones are exposed unless they are labelled cluster-local
+func inferenceServiceExposed(isvc unstructured.Unstructured) bool {
+	mode := isvc.GetAnnotations()["serving.kserve.io/deploymentMode"]
+	if mode == "" {
+		mode, _, _ = unstructured.NestedString(isvc.Object, "status", "deploymentMode")
+	}
+	labels := isvc.GetLabels()
+	if mode == "RawDeployment" || mode == "ModelMesh" {
+		return labels["networking.kserve.io/visibility"] == "exposed"
+	}
+	return labels["networking.knative.dev/visibility"] != "cluster-local" && labels["serving.knative.dev/visibility"] != "cluster-local"
+}
+
+func inferenceServiceSource(isvc unstructured.Unstructured) string {
+	connection, _, _ := unstructured.NestedString(isvc.Object, "spec", "predictor", "model", "storage", "key")
+	path, _, _ := unstructured.NestedString(isvc.Object, "spec", "predictor", "model", "storage", "path")
+	if connection != "" {
+		return fmt.Sprintf("connection %s, path %s", connection, path)
+	}
+	if storageURI, _, _ := unstructured.NestedString(isvc.Object, "spec", "predictor", "model", "storageUri"); storageURI != "" {
+		return storageURI
+	}
+	return "unknown"
+}
+
+func valueOrUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
+
+func ScaleModelDeployment(ctx context.Context, req *mcp.CallToolRequest, input ScaleModelDeploymentInput) (*mcp.CallToolResult, ModelDeploymentOutput, error) {
+	maxReplicas := input.MaxReplicas
+	if maxReplicas == 0 {
+		maxReplicas = input.MinReplicas
+	}
+	if input.MinReplicas < 0 || maxReplicas < input.MinReplicas || maxReplicas == 0 {
+		return nil, ModelDeploymentOutput{}, fmt.Errorf("invalid replicas min %d max %d, max has to be positive and at least min", input.MinReplicas, maxReplicas)
+	}
+
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, ModelDeploymentOutput{}, err
+	}
+
+	patch := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"predictor": map[string]interface{}{
+				"minReplicas": input.MinReplicas,
+				"maxReplicas": maxReplicas,
+			},
+		},
+	}
+	patchBytes, err := json.Marshal(patch)
+	if err != nil {
+		return nil, ModelDeploymentOutput{}, fmt.Errorf("failed to marshal patch: %v", err)
+	}
+
+	_, err = dyn.Resource(inferenceServicesGVR).Namespace(input.Namespace).Patch(ctx, input.ModelName, k8stypes.MergePatchType, patchBytes, metav1.PatchOptions{})
+	if err != nil {
+		return nil, ModelDeploymentOutput{}, fmt.Errorf("failed to scale model deployment %s: %v", input.ModelName, err)
+	}
+
+	return nil, ModelDeploymentOutput{Message: fmt.Sprintf("Model deployment %s was scaled to min %d, max %d replicas", input.ModelName, input.MinReplicas, maxReplicas)}, nil
+}
+
+func ChangeModelDeploymentStatus(ctx context.Context, req *mcp.CallToolRequest, input ChangeModelDeploymentStatusInput) (*mcp.CallToolResult, ModelDeploymentOutput, error) {
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, ModelDeploymentOutput{}, err
+	}
+
+	current, err := dyn.Resource(inferenceServicesGVR).Namespace(input.Namespace).Get(ctx, input.ModelName, metav1.GetOptions{})
+	if err != nil {
+		return nil, ModelDeploymentOutput{}, fmt.Errorf("failed to get model deployment %s: %v", input.ModelName, err)
+	}
+	stopped := current.GetAnnotations()["serving.kserve.io/stop"] == "true"
+	if (input.Status == Stopped && stopped) || (input.Status == Running && !stopped) {
+		return nil, ModelDeploymentOutput{Message: fmt.Sprintf("Model deployment %s is already %s", input.ModelName, input.Status)}, nil
+	}
+
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				"serving.kserve.io/stop": fmt.Sprintf("%t", input.Status == Stopped),
+			},
+		},
+	}
+	patchBytes, err := json.Marshal(patch)
+	if err != nil {
+		return nil, ModelDeploymentOutput{}, fmt.Errorf("failed to marshal patch: %v", err)
+	}
+
+	_, err = dyn.Resource(inferenceServicesGVR).Namespace(input.Namespace).Patch(ctx, input.ModelName, k8stypes.MergePatchType, patchBytes, metav1.PatchOptions{})
+	if err != nil {
+		return nil, ModelDeploymentOutput{}, fmt.Errorf("failed to %s model deployment %s: %v", input.Status, input.ModelName, err)
+	}
+
+	return nil, ModelDeploymentOutput{Message: fmt.Sprintf("Model deployment %s is %s", input.ModelName, input.Status)}, nil
+}
+
+// Deletes the InferenceService together with the runtime and token auth objects created for it
+func DeleteModelDeployment(ctx context.Context, req *mcp.CallToolRequest, input ModelDeploymentInput) (*mcp.CallToolResult, ModelDeploymentOutput, error) {
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, ModelDeploymentOutput{}, err
+	}
+
+	isvc, err := dyn.Resource(inferenceServicesGVR).Namespace(input.Namespace).Get(ctx, input.ModelName, metav1.GetOptions{})
+	if err != nil {
+		return nil, ModelDeploymentOutput{}, fmt.Errorf("failed to get model deployment %s: %v", input.ModelName, err)
+	}
+	runtimeName, _, _ := unstructured.NestedString(isvc.Object, "spec", "predictor", "model", "runtime")
+
+	err = dyn.Resource(inferenceServicesGVR).Namespace(input.Namespace).Delete(ctx, input.ModelName, metav1.DeleteOptions{})
+	if err != nil {
+		return nil, ModelDeploymentOutput{}, fmt.Errorf("failed to delete model deployment %s: %v", input.ModelName, err)
+	}
+
+	// runtimes are per deployment copies in the dashboard, shared runtimes are left alone
+	if runtimeName == input.ModelName {
+		servingRuntime, err := dyn.Resource(servingRuntimesGVR).Namespace(input.Namespace).Get(ctx, runtimeName, metav1.GetOptions{})
+		if err == nil && servingRuntime.GetLabels()["opendatahub.io/dashboard"] == "true" {
+			if err := dyn.Resource(servingRuntimesGVR).Namespace(input.Namespace).Delete(ctx, runtimeName, metav1.DeleteOptions{}); err != nil {
+				return nil, ModelDeploymentOutput{}, fmt.Errorf("failed to delete serving runtime %s: %v", runtimeName, err)
+			}
+		}
+	}
+
+	if err := deleteModelServiceAccount(ctx, dyn, input.Namespace, input.ModelName); err != nil {
+		return nil, ModelDeploymentOutput{}, err
+	}
+
+	return nil, ModelDeploymentOutput{Message: fmt.Sprintf("Model deployment %s was deleted", input.ModelName)}, nil
+}
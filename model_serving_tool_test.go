@@ -0,0 +1,373 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newUnstructuredInferenceService(name, namespace, url string, ready bool) *unstructured.Unstructured {
+	readyStatus := "False"
+	if ready {
+		readyStatus = "True"
+	}
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"predictor": map[string]interface{}{
+				"minReplicas": int64(1),
+				"maxReplicas": int64(2),
+				"model": map[string]interface{}{
+					"modelFormat": map[string]interface{}{"name": "vLLM"},
+					"runtime":     name,
+					"storage":     map[string]interface{}{"key": "models", "path": "granite"},
+				},
+			},
+		},
+		"status": map[string]interface{}{
+			"url":     url,
+			"address": map[string]interface{}{"url": url},
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": readyStatus, "reason": "RevisionMissing"},
+			},
+		},
+	}}
+	u.SetGroupVersionKind(inferenceServicesGVR.GroupVersion().WithKind("InferenceService"))
+	u.SetName(name)
+	u.SetNamespace(namespace)
+	u.SetAnnotations(map[string]string{"serving.kserve.io/deploymentMode": "RawDeployment"})
+	return u
+}
+
+func TestListModelDeployments(t *testing.T) {
+	orig := getDynamicClient
+	defer func() { getDynamicClient = orig }()
+
+	scheme := runtime.NewScheme()
+	client := dynamicfake.NewSimpleDynamicClient(scheme,
+		newUnstructuredInferenceService("granite", "ns1", "http://granite.ns1.svc.cluster.local", true),
+		newUnstructuredInferenceService("broken", "ns2", "", false),
+	)
+	getDynamicClient = func() (dynamic.Interface, error) {
+		return client, nil
+	}
+
+	_, out, err := ListModelDeployments(context.Background(), nil, ListModelDeploymentsInput{Namespace: "ns1"})
+	if err != nil {
+		t.Fatalf("ListModelDeployments returned error: %v", err)
+	}
+	expected := "- granite: ready\n" +
+		"  Model format: vLLM, runtime: granite\n" +
+		"  Replicas: min 1, max 2\n" +
+		"  Internal URL: http://granite.ns1.svc.cluster.local\n" +
+		"  Source: connection models, path granite\n"
+	if out.Deployments != expected {
+		t.Errorf("unexpected output: %q", out.Deployments)
+	}
+
+	_, out, err = ListModelDeployments(context.Background(), nil, ListModelDeploymentsInput{})
+	if err != nil {
+		t.Fatalf("ListModelDeployments returned error: %v", err)
+	}
+	if !strings.Contains(out.Deployments, "- broken in ns2: not ready (RevisionMissing)\n") {
+		t.Errorf("expected broken deployment in output, got: %q", out.Deployments)
+	}
+	// serverless deployments are exposed by default and hidden by the cluster-local label
+	serverless := newUnstructuredInferenceService("serverless", "ns3", "https://serverless-ns3.apps.example.com", true)
+	serverless.SetAnnotations(nil)
+	clusterLocal := newUnstructuredInferenceService("cluster-local", "ns3", "https://cluster-local-ns3.apps.example.com", true)
+	clusterLocal.SetAnnotations(map[string]string{"serving.kserve.io/deploymentMode": "Serverless"})
+	clusterLocal.SetLabels(map[string]string{"networking.knative.dev/visibility": "cluster-local"})
+	client = dynamicfake.NewSimpleDynamicClient(scheme, serverless, clusterLocal)
+	_, out, err = ListModelDeployments(context.Background(), nil, ListModelDeploymentsInput{Namespace: "ns3"})
+	if err != nil {
+		t.Fatalf("ListModelDeployments returned error: %v", err)
+	}
+	if !strings.Contains(out.Deployments, "  External URL: https://serverless-ns3.apps.example.com\n") {
+		t.Errorf("expected external URL of the serverless deployment, got: %q", out.Deployments)
+	}
+	if strings.Contains(out.Deployments, "External URL: https://cluster-local-ns3") {
+		t.Errorf("expected no external URL for the cluster-local deployment, got: %q", out.Deployments)
+	}
+}
+
+func newUnstructuredServingRuntimeTemplate(name, displayName string, formats ...string) *unstructured.Unstructured {
+	var supportedFormats []interface{}
+	for _, format := range formats {
+		supportedFormats = append(supportedFormats, map[string]interface{}{"name": format, "autoSelect": true})
+	}
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"objects": []interface{}{
+			map[string]interface{}{
+				"apiVersion": "serving.kserve.io/v1alpha1",
+				"kind":       "ServingRuntime",
+				"metadata": map[string]interface{}{
+					"name":        name,
+					"annotations": map[string]interface{}{"openshift.io/display-name": displayName},
+				},
+				"spec": map[string]interface{}{
+					"supportedModelFormats": supportedFormats,
+					"containers":            []interface{}{map[string]interface{}{"name": "kserve-container", "image": "runtime:latest"}},
+				},
+			},
+		},
+	}}
+	u.SetGroupVersionKind(templatesGVR.GroupVersion().WithKind("Template"))
+	u.SetName(name)
+	u.SetNamespace(applicationsNamespace)
+	u.SetLabels(map[string]string{"opendatahub.io/dashboard": "true"})
+	return u
+}
+
+func TestDeployModel(t *testing.T) {
+	orig := getDynamicClient
+	defer func() { getDynamicClient = orig }()
+
+	scheme := runtime.NewScheme()
+	client := dynamicfake.NewSimpleDynamicClient(scheme,
+		newUnstructuredServingRuntimeTemplate("vllm-runtime-template", "vLLM NVIDIA GPU ServingRuntime for KServe", "vLLM"),
+		newUnstructuredConnection("models", "ns1", "s3", nil),
+	)
+	getDynamicClient = func() (dynamic.Interface, error) {
+		return client, nil
+	}
+	ctx := context.Background()
+
+	input := DeployModelInput{
+		Namespace:          "ns1",
+		ModelName:          "granite",
+		RuntimeTemplate:    "vLLM NVIDIA GPU ServingRuntime for KServe",
+		ConnectionName:     "models",
+		ModelPath:          "granite-3b",
+		Accelerator:        "nvidia.com/gpu",
+		AcceleratorProfile: "migrated-gpu",
+		ExternalRoute:      true,
+		TokenAuth:          true,
+	}
+	if _, _, err := DeployModel(ctx, nil, DeployModelInput{Namespace: "ns1", ModelName: "granite", RuntimeTemplate: input.RuntimeTemplate}); err == nil {
+		t.Errorf("expected error without model location")
+	}
+	bad := input
+	bad.ModelFormat = "onnx"
+	if _, _, err := DeployModel(ctx, nil, bad); err == nil {
+		t.Errorf("expected error for model format not supported by the runtime")
+	}
+
+	_, out, err := DeployModel(ctx, nil, input)
+	if err != nil {
+		t.Fatalf("DeployModel returned error: %v", err)
+	}
+	if out.Message != "Model granite was succesfully deployed with runtime vllm-runtime-template!" {
+		t.Errorf("unexpected message: %q", out.Message)
+	}
+
+	servingRuntime, err := client.Resource(servingRuntimesGVR).Namespace("ns1").Get(ctx, "granite", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected serving runtime to be created: %v", err)
+	}
+	if servingRuntime.GetAnnotations()["opendatahub.io/template-name"] != "vllm-runtime-template" {
+		t.Errorf("expected template name annotation, got: %v", servingRuntime.GetAnnotations())
+	}
+	if servingRuntime.GetAnnotations()["opendatahub.io/accelerator-name"] != "migrated-gpu" {
+		t.Errorf("expected accelerator profile annotation, got: %v", servingRuntime.GetAnnotations())
+	}
+
+	isvc, err := client.Resource(inferenceServicesGVR).Namespace("ns1").Get(ctx, "granite", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected inference service to be created: %v", err)
+	}
+	if source := inferenceServiceSource(*isvc); source != "connection models, path granite-3b" {
+		t.Errorf("unexpected model source: %q", source)
+	}
+	if gpus, _, _ := unstructured.NestedString(isvc.Object, "spec", "predictor", "model", "resources", "limits", "nvidia.com/gpu"); gpus != "1" {
+		t.Errorf("expected one GPU limit, got: %q", gpus)
+	}
+	if isvc.GetLabels()["networking.kserve.io/visibility"] != "exposed" || isvc.GetAnnotations()["security.opendatahub.io/enable-auth"] != "true" {
+		t.Errorf("expected exposed route and token auth, got labels %v annotations %v", isvc.GetLabels(), isvc.GetAnnotations())
+	}
+	if _, err := client.Resource(serviceAccountsGVR).Namespace("ns1").Get(ctx, "granite-sa", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected service account for token auth: %v", err)
+	}
+}
+
+func TestDeployModelRollback(t *testing.T) {
+	orig := getDynamicClient
+	defer func() { getDynamicClient = orig }()
+
+	scheme := runtime.NewScheme()
+	client := dynamicfake.NewSimpleDynamicClient(scheme,
+		newUnstructuredServingRuntimeTemplate("vllm-runtime-template", "vLLM NVIDIA GPU ServingRuntime for KServe", "vLLM"),
+	)
+	failing := "rolebindings"
+	client.PrependReactor("create", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetResource().Resource == failing {
+			return true, nil, fmt.Errorf("create %s denied", failing)
+		}
+		return false, nil, nil
+	})
+	getDynamicClient = func() (dynamic.Interface, error) {
+		return client, nil
+	}
+	ctx := context.Background()
+
+	input := DeployModelInput{
+		Namespace:       "ns1",
+		ModelName:       "granite",
+		RuntimeTemplate: "vLLM NVIDIA GPU ServingRuntime for KServe",
+		StorageURI:      "oci://quay.io/models/granite:1.0",
+		TokenAuth:       true,
+	}
+	// a failure on any of the created objects must leave nothing behind so the deploy can be retried
+	for _, failing = range []string{"rolebindings", "inferenceservices"} {
+		if _, _, err := DeployModel(ctx, nil, input); err == nil {
+			t.Fatalf("expected error when creating %s fails", failing)
+		}
+		for _, object := range []struct {
+			gvr  schema.GroupVersionResource
+			name string
+		}{
+			{servingRuntimesGVR, "granite"},
+			{serviceAccountsGVR, "granite-sa"},
+			{rolesGVR, "granite-view-role"},
+			{roleBindingsGVR, "granite-view"},
+		} {
+			if _, err := client.Resource(object.gvr).Namespace("ns1").Get(ctx, object.name, metav1.GetOptions{}); err == nil {
+				t.Errorf("expected %s %s to be removed after %s failed", object.gvr.Resource, object.name, failing)
+			}
+		}
+	}
+
+	failing = ""
+	input.Accelerator = "nvidia.com/gpu"
+	if _, _, err := DeployModel(ctx, nil, input); err != nil {
+		t.Errorf("expected retry to succeed, got: %v", err)
+	}
+	// the resource name is no accelerator profile, without a profile no annotation is set
+	servingRuntime, _ := client.Resource(servingRuntimesGVR).Namespace("ns1").Get(ctx, "granite", metav1.GetOptions{})
+	if _, ok := servingRuntime.GetAnnotations()["opendatahub.io/accelerator-name"]; ok {
+		t.Errorf("expected no accelerator profile annotation, got: %v", servingRuntime.GetAnnotations())
+	}
+}
+
+func TestListServingRuntimes(t *testing.T) {
+	orig := getDynamicClient
+	defer func() { getDynamicClient = orig }()
+
+	template := newUnstructuredServingRuntimeTemplate("ovms", "OpenVINO Model Server", "onnx", "openvino_ir")
+	template.SetAnnotations(map[string]string{
+		"opendatahub.io/apiProtocol":         "REST",
+		"opendatahub.io/modelServingSupport": `["single","multi"]`,
+	})
+	scheme := runtime.NewScheme()
+	client := dynamicfake.NewSimpleDynamicClient(scheme, template)
+	getDynamicClient = func() (dynamic.Interface, error) {
+		return client, nil
+	}
+
+	_, out, err := ListServingRuntimes(context.Background(), nil, ListServingRuntimesInput{})
+	if err != nil {
+		t.Fatalf("ListServingRuntimes returned error: %v", err)
+	}
+	expected := "Runtime: OpenVINO Model Server\n Template: ovms\n Model formats: onnx, openvino_ir\n API protocol: REST\n Serving platforms: single, multi\n Accelerators: \n"
+	if out.Runtimes != expected {
+		t.Errorf("unexpected output: %q", out.Runtimes)
+	}
+}
+
+func TestModelDeploymentLifecycle(t *testing.T) {
+	orig := getDynamicClient
+	defer func() { getDynamicClient = orig }()
+
+	servingRuntime := &unstructured.Unstructured{}
+	servingRuntime.SetGroupVersionKind(servingRuntimesGVR.GroupVersion().WithKind("ServingRuntime"))
+	servingRuntime.SetName("granite")
+	servingRuntime.SetNamespace("ns1")
+	servingRuntime.SetLabels(map[string]string{"opendatahub.io/dashboard": "true"})
+
+	// token auth objects of the deployment and a user's own binding that happens to match the naming pattern
+	serviceAccount := &unstructured.Unstructured{}
+	serviceAccount.SetGroupVersionKind(serviceAccountsGVR.GroupVersion().WithKind("ServiceAccount"))
+	serviceAccount.SetName("granite-sa")
+	serviceAccount.SetNamespace("ns1")
+	serviceAccount.SetLabels(map[string]string{"opendatahub.io/dashboard": "true"})
+	userBinding := &unstructured.Unstructured{}
+	userBinding.SetGroupVersionKind(roleBindingsGVR.GroupVersion().WithKind("RoleBinding"))
+	userBinding.SetName("granite-view")
+	userBinding.SetNamespace("ns1")
+
+	scheme := runtime.NewScheme()
+	client := dynamicfake.NewSimpleDynamicClient(scheme,
+		newUnstructuredInferenceService("granite", "ns1", "", true),
+		servingRuntime,
+		serviceAccount,
+		userBinding,
+	)
+	getDynamicClient = func() (dynamic.Interface, error) {
+		return client, nil
+	}
+	ctx := context.Background()
+
+	_, out, err := ChangeModelDeploymentStatus(ctx, nil, ChangeModelDeploymentStatusInput{Namespace: "ns1", ModelName: "granite", Status: Running})
+	if err != nil {
+		t.Fatalf("ChangeModelDeploymentStatus returned error: %v", err)
+	}
+	if out.Message != "Model deployment granite is already running" {
+		t.Errorf("unexpected message: %q", out.Message)
+	}
+
+	_, out, err = ChangeModelDeploymentStatus(ctx, nil, ChangeModelDeploymentStatusInput{Namespace: "ns1", ModelName: "granite", Status: Stopped})
+	if err != nil {
+		t.Fatalf("ChangeModelDeploymentStatus returned error: %v", err)
+	}
+	if out.Message != "Model deployment granite is stopped" {
+		t.Errorf("unexpected message: %q", out.Message)
+	}
+
+	_, out, err = ChangeModelDeploymentStatus(ctx, nil, ChangeModelDeploymentStatusInput{Namespace: "ns1", ModelName: "granite", Status: Stopped})
+	if err != nil {
+		t.Fatalf("ChangeModelDeploymentStatus returned error: %v", err)
+	}
+	if out.Message != "Model deployment granite is already stopped" {
+		t.Errorf("unexpected message: %q", out.Message)
+	}
+
+	if _, _, err := ScaleModelDeployment(ctx, nil, ScaleModelDeploymentInput{Namespace: "ns1", ModelName: "granite", MinReplicas: 3, MaxReplicas: 2}); err == nil {
+		t.Errorf("expected error when max replicas is lower than min replicas")
+	}
+	_, out, err = ScaleModelDeployment(ctx, nil, ScaleModelDeploymentInput{Namespace: "ns1", ModelName: "granite", MinReplicas: 2, MaxReplicas: 4})
+	if err != nil {
+		t.Fatalf("ScaleModelDeployment returned error: %v", err)
+	}
+	if out.Message != "Model deployment granite was scaled to min 2, max 4 replicas" {
+		t.Errorf("unexpected message: %q", out.Message)
+	}
+	scaled, _ := client.Resource(inferenceServicesGVR).Namespace("ns1").Get(ctx, "granite", metav1.GetOptions{})
+	if maxReplicas, _, _ := unstructured.NestedFieldNoCopy(scaled.Object, "spec", "predictor", "maxReplicas"); maxReplicas != int64(4) {
+		t.Errorf("expected max replicas 4, got: %v", maxReplicas)
+	}
+
+	_, out, err = DeleteModelDeployment(ctx, nil, ModelDeploymentInput{Namespace: "ns1", ModelName: "granite"})
+	if err != nil {
+		t.Fatalf("DeleteModelDeployment returned error: %v", err)
+	}
+	if out.Message != "Model deployment granite was deleted" {
+		t.Errorf("unexpected message: %q", out.Message)
+	}
+	if _, err := client.Resource(servingRuntimesGVR).Namespace("ns1").Get(ctx, "granite", metav1.GetOptions{}); err == nil {
+		t.Errorf("expected serving runtime to be deleted")
+	}
+	if _, err := client.Resource(serviceAccountsGVR).Namespace("ns1").Get(ctx, "granite-sa", metav1.GetOptions{}); err == nil {
+		t.Errorf("expected service account to be deleted")
+	}
+	if _, err := client.Resource(roleBindingsGVR).Namespace("ns1").Get(ctx, "granite-view", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected role binding not created by the dashboard to be kept: %v", err)
+	}
+}
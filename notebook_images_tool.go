@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+// label the dashboard puts on custom (bring your own notebook) images
+const byonLabelSelector = "app.kubernetes.io/created-by=byon"
+
+var nonNameCharacters = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Imports a custom notebook image the way the dashboard's Notebook images page does
+func ImportNotebookImage(ctx context.Context, req *mcp.CallToolRequest, input ImportNotebookImageInput) (*mcp.CallToolResult, NotebookImageOutput, error) {
+	if input.ImageURL == "" || strings.ContainsAny(input.ImageURL, " \t\n") {
+		return nil, NotebookImageOutput{}, fmt.Errorf("invalid image URL %q", input.ImageURL)
+	}
+	name := notebookImageStreamName(input.DisplayName)
+	if name == "custom-" {
+		return nil, NotebookImageOutput{}, fmt.Errorf("display name %q has no letters or digits", input.DisplayName)
+	}
+
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, NotebookImageOutput{}, err
+	}
+
+	existing, err := findCustomNotebookImage(ctx, dyn, input.DisplayName)
+	if err != nil {
+		return nil, NotebookImageOutput{}, err
+	}
+	if existing != nil {
+		return nil, NotebookImageOutput{}, fmt.Errorf("custom image %s already exists", input.DisplayName)
+	}
+
+	imageStream, err := newNotebookImageStream(name, input)
+	if err != nil {
+		return nil, NotebookImageOutput{}, err
+	}
+	_, err = dyn.Resource(imageStreamsGVR).Namespace(applicationsNamespace).Create(ctx, imageStream, metav1.CreateOptions{})
+	if err != nil {
+		return nil, NotebookImageOutput{}, fmt.Errorf("failed to import notebook image: %v", err)
+	}
+
+	return nil, NotebookImageOutput{Message: fmt.Sprintf("Image %s was succesfully imported as %s with tag %s!", input.DisplayName, name, imageURLTag(input.ImageURL))}, nil
+}
+
+// Enables or disables a custom notebook image, disabled images are not offered for new workbenches
+func ChangeNotebookImageStatus(ctx context.Context, req *mcp.CallToolRequest, input NotebookImageStatusInput) (*mcp.CallToolResult, NotebookImageOutput, error) {
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, NotebookImageOutput{}, err
+	}
+
+	image, err := getCustomNotebookImage(ctx, dyn, input.Name)
+	if err != nil {
+		return nil, NotebookImageOutput{}, err
+	}
+
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{
+				"opendatahub.io/notebook-image": fmt.Sprintf("%t", input.Enabled),
+			},
+		},
+	}
+	patchBytes, err := json.Marshal(patch)
+	if err != nil {
+		return nil, NotebookImageOutput{}, fmt.Errorf("failed to marshal patch: %v", err)
+	}
+
+	_, err = dyn.Resource(imageStreamsGVR).Namespace(applicationsNamespace).Patch(ctx, image.GetName(), k8stypes.MergePatchType, patchBytes, metav1.PatchOptions{})
+	if err != nil {
+		return nil, NotebookImageOutput{}, fmt.Errorf("failed to change status of notebook image %s: %v", input.Name, err)
+	}
+
+	status := "disabled"
+	if input.Enabled {
+		status = "enabled"
+	}
+	return nil, NotebookImageOutput{Message: fmt.Sprintf("Image %s is %s", input.Name, status)}, nil
+}
+
+func DeleteNotebookImage(ctx context.Context, req *mcp.CallToolRequest, input NotebookImageInput) (*mcp.CallToolResult, NotebookImageOutput, error) {
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, NotebookImageOutput{}, err
+	}
+
+	image, err := getCustomNotebookImage(ctx, dyn, input.Name)
+	if err != nil {
+		return nil, NotebookImageOutput{}, err
+	}
+
+	err = dyn.Resource(imageStreamsGVR).Namespace(applicationsNamespace).Delete(ctx, image.GetName(), metav1.DeleteOptions{})
+	if err != nil {
+		return nil, NotebookImageOutput{}, fmt.Errorf("failed to delete notebook image %s: %v", input.Name, err)
+	}
+
+	return nil, NotebookImageOutput{Message: fmt.Sprintf("Image %s was deleted", input.Name)}, nil
+}
+
+func newNotebookImageStream(name string, input ImportNotebookImageInput) (*unstructured.Unstructured, error) {
+	software, err := json.Marshal(nonNilPackages(input.Software))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal software: %v", err)
+	}
+	packages, err := json.Marshal(nonNilPackages(input.Packages))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal packages: %v", err)
+	}
+
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "image.openshift.io/v1",
+			"kind":       "ImageStream",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": applicationsNamespace,
+				"labels": map[string]interface{}{
+					"app.kubernetes.io/created-by":  "byon",
+					"opendatahub.io/dashboard":      "true",
+					"opendatahub.io/notebook-image": "true",
+				},
+				"annotations": map[string]interface{}{
+					"opendatahub.io/notebook-image-name":      input.DisplayName,
+					"opendatahub.io/notebook-image-desc":      input.Description,
+					"opendatahub.io/notebook-image-url":       input.ImageURL,
+					"opendatahub.io/notebook-image-creator":   "mcp",
+					"opendatahub.io/recommended-accelerators": "[]",
+				},
+			},
+			"spec": map[string]interface{}{
+				"lookupPolicy": map[string]interface{}{
+					"local": true,
+				},
+				"tags": []interface{}{
+					map[string]interface{}{
+						"name": imageURLTag(input.ImageURL),
+						"annotations": map[string]interface{}{
+							"opendatahub.io/notebook-software":            string(software),
+							"opendatahub.io/notebook-python-dependencies": string(packages),
+							"openshift.io/imported-from":                  input.ImageURL,
+						},
+						"from": map[string]interface{}{
+							"kind": "DockerImage",
+							"name": input.ImageURL,
+						},
+						"importPolicy": map[string]interface{}{
+							"scheduled": false,
+						},
+						"referencePolicy": map[string]interface{}{
+							"type": "Source",
+						},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// getCustomNotebookImage returns the custom image with the given display or image stream name,
+// built-in images can not be changed
+func getCustomNotebookImage(ctx context.Context, dyn dynamic.Interface, name string) (*unstructured.Unstructured, error) {
+	image, err := findCustomNotebookImage(ctx, dyn, name)
+	if err != nil {
+		return nil, err
+	}
+	if image != nil {
+		return image, nil
+	}
+
+	_, err = dyn.Resource(imageStreamsGVR).Namespace(applicationsNamespace).Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		return nil, fmt.Errorf("image %s is not a custom image", name)
+	}
+	if !errors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get image %s: %v", name, err)
+	}
+	return nil, fmt.Errorf("custom image %s not found", name)
+}
+
+func findCustomNotebookImage(ctx context.Context, dyn dynamic.Interface, name string) (*unstructured.Unstructured, error) {
+	images, err := dyn.Resource(imageStreamsGVR).Namespace(applicationsNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: byonLabelSelector,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list custom images: %v", err)
+	}
+	for _, image := range images.Items {
+		if image.GetName() == name || image.GetAnnotations()["opendatahub.io/notebook-image-name"] == name {
+			return &image, nil
+		}
+	}
+	return nil, nil
+}
+
+// notebookImageStreamName derives the image stream name from the display name like the dashboard - f.e. custom-my-image
+func notebookImageStreamName(displayName string) string {
+	return "custom-" + strings.Trim(nonNameCharacters.ReplaceAllString(strings.ToLower(displayName), "-"), "-")
+}
+
+// imageURLTag returns the tag of an image URL, latest when it has none or is referenced by digest
+func imageURLTag(imageURL string) string {
+	lastSegment := imageURL[strings.LastIndex(imageURL, "/")+1:]
+	if strings.Contains(lastSegment, "@") {
+		return "latest"
+	}
+	if i := strings.LastIndex(lastSegment, ":"); i >= 0 && i < len(lastSegment)-1 {
+		return lastSegment[i+1:]
+	}
+	return "latest"
+}
+
+// nonNilPackages makes empty lists marshal to [] which the dashboard expects instead of null
+func nonNilPackages(packages []ImagePackage) []ImagePackage {
+	if packages == nil {
+		return []ImagePackage{}
+	}
+	return packages
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestImageURLTag(t *testing.T) {
+	for url, expected := range map[string]string{
+		"quay.io/org/notebook:1.0":               "1.0",
+		"quay.io/org/notebook":                   "latest",
+		"registry.example.com:5000/org/notebook": "latest",
+		"quay.io/org/notebook@sha256:abc":        "latest",
+	} {
+		if tag := imageURLTag(url); tag != expected {
+			t.Errorf("expected tag %s for %s, got %s", expected, url, tag)
+		}
+	}
+	if name := notebookImageStreamName("My PyTorch (CUDA) Image!"); name != "custom-my-pytorch-cuda-image" {
+		t.Errorf("unexpected image stream name: %s", name)
+	}
+}
+
+func TestNotebookImages(t *testing.T) {
+	orig := getDynamicClient
+	defer func() { getDynamicClient = orig }()
+
+	scheme := runtime.NewScheme()
+	client := dynamicfake.NewSimpleDynamicClient(scheme, newPyTorchImageStream())
+	getDynamicClient = func() (dynamic.Interface, error) {
+		return client, nil
+	}
+	ctx := context.Background()
+
+	input := ImportNotebookImageInput{
+		ImageURL:    "quay.io/team/llm-notebook:2.1",
+		DisplayName: "LLM Fine-tuning",
+		Description: "PyTorch with PEFT",
+		Software:    []ImagePackage{{Name: "Python", Version: "v3.11"}},
+		Packages:    []ImagePackage{{Name: "PyTorch", Version: "2.4"}, {Name: "PEFT", Version: "0.12"}},
+	}
+	_, out, err := ImportNotebookImage(ctx, nil, input)
+	if err != nil {
+		t.Fatalf("ImportNotebookImage returned error: %v", err)
+	}
+	if out.Message != "Image LLM Fine-tuning was succesfully imported as custom-llm-fine-tuning with tag 2.1!" {
+		t.Errorf("unexpected message: %q", out.Message)
+	}
+	if _, _, err := ImportNotebookImage(ctx, nil, input); err == nil {
+		t.Errorf("expected error when importing the same image twice")
+	}
+
+	images, err := GetImages(ctx)
+	if err != nil {
+		t.Fatalf("GetImages returned error: %v", err)
+	}
+	var imported *ImageDef
+	for i := range images {
+		if images[i].Name == "LLM Fine-tuning" {
+			imported = &images[i]
+		}
+	}
+	if imported == nil || len(imported.Tags) != 1 || imported.Tags[0].PythonVersion != "3.11" || len(imported.Tags[0].Packages) != 2 {
+		t.Fatalf("expected imported image with its software in the catalog, got: %+v", images)
+	}
+
+	_, out, err = ChangeNotebookImageStatus(ctx, nil, NotebookImageStatusInput{Name: "LLM Fine-tuning", Enabled: false})
+	if err != nil {
+		t.Fatalf("ChangeNotebookImageStatus returned error: %v", err)
+	}
+	if out.Message != "Image LLM Fine-tuning is disabled" {
+		t.Errorf("unexpected message: %q", out.Message)
+	}
+	if images, _ := GetImages(ctx); len(images) != 1 {
+		t.Errorf("expected disabled image to be hidden from the catalog, got: %+v", images)
+	}
+	if _, _, err := ChangeNotebookImageStatus(ctx, nil, NotebookImageStatusInput{Name: "custom-llm-fine-tuning", Enabled: true}); err != nil {
+		t.Fatalf("ChangeNotebookImageStatus returned error: %v", err)
+	}
+	if images, _ := GetImages(ctx); len(images) != 2 {
+		t.Errorf("expected enabled image in the catalog, got: %+v", images)
+	}
+
+	if _, _, err := DeleteNotebookImage(ctx, nil, NotebookImageInput{Name: "pytorch"}); err == nil {
+		t.Errorf("expected error when deleting a built-in image")
+	}
+	if _, _, err := DeleteNotebookImage(ctx, nil, NotebookImageInput{Name: "LLM Fine-tuning"}); err != nil {
+		t.Fatalf("DeleteNotebookImage returned error: %v", err)
+	}
+	if _, err := client.Resource(imageStreamsGVR).Namespace(applicationsNamespace).Get(ctx, "custom-llm-fine-tuning", metav1.GetOptions{}); err == nil {
+		t.Errorf("expected image stream to be deleted")
+	}
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// roles offered by the dashboard Permissions tab
+var projectRoles = []string{"admin", "edit", "view"}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+type roleBindingSubject struct {
+	Kind string
+	Name string
+	Role string
+}
+
+func ListPermissions(ctx context.Context, req *mcp.CallToolRequest, input ListWorkbenchesInput) (*mcp.CallToolResult, ListPermissionsOutput, error) {
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, ListPermissionsOutput{}, err
+	}
+
+	bindings, err := dyn.Resource(roleBindingsGVR).Namespace(input.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, ListPermissionsOutput{}, fmt.Errorf("failed to list role bindings: %v", err)
+	}
+
+	msg := ""
+	for _, binding := range bindings.Items {
+		for _, subject := range roleBindingSubjects(binding) {
+			if !isProjectRole(subject.Role) || (subject.Kind != "User" && subject.Kind != "Group") {
+				continue
+			}
+			msg += fmt.Sprintf("- %s %s: %s (role binding %s)\n", subject.Kind, subject.Name, subject.Role, binding.GetName())
+		}
+	}
+	return nil, ListPermissionsOutput{Permissions: msg}, nil
+}
+
+func GrantPermission(ctx context.Context, req *mcp.CallToolRequest, input GrantPermissionInput) (*mcp.CallToolResult, PermissionOutput, error) {
+	if err := validatePermissionInput(input.SubjectKind, input.Role); err != nil {
+		return nil, PermissionOutput{}, err
+	}
+	if input.Role == "" {
+		return nil, PermissionOutput{}, fmt.Errorf("role must be one of %s", strings.Join(projectRoles, ", "))
+	}
+
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, PermissionOutput{}, err
+	}
+
+	bindings, err := dyn.Resource(roleBindingsGVR).Namespace(input.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, PermissionOutput{}, fmt.Errorf("failed to list role bindings: %v", err)
+	}
+	for _, binding := range bindings.Items {
+		for _, subject := range roleBindingSubjects(binding) {
+			if subject.Kind == input.SubjectKind && subject.Name == input.SubjectName && subject.Role == input.Role {
+				return nil, PermissionOutput{Message: fmt.Sprintf("%s %s already has %s access to project %s", input.SubjectKind, input.SubjectName, input.Role, input.Namespace)}, nil
+			}
+		}
+	}
+
+	name := permissionBindingName(input.SubjectKind, input.SubjectName, input.Role)
+	binding := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "rbac.authorization.k8s.io/v1",
+			"kind":       "RoleBinding",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": input.Namespace,
+				"labels": map[string]interface{}{
+					"opendatahub.io/dashboard":       "true",
+					"opendatahub.io/project-sharing": "true",
+				},
+			},
+			"roleRef": map[string]interface{}{
+				"apiGroup": "rbac.authorization.k8s.io",
+				"kind":     "ClusterRole",
+				"name":     input.Role,
+			},
+			"subjects": []interface{}{
+				map[string]interface{}{
+					"apiGroup": "rbac.authorization.k8s.io",
+					"kind":     input.SubjectKind,
+					"name":     input.SubjectName,
+				},
+			},
+		},
+	}
+
+	_, err = dyn.Resource(roleBindingsGVR).Namespace(input.Namespace).Create(ctx, binding, metav1.CreateOptions{})
+	if err != nil {
+		return nil, PermissionOutput{}, fmt.Errorf("failed to create role binding: %v", err)
+	}
+
+	return nil, PermissionOutput{Message: fmt.Sprintf("%s %s was granted %s access to project %s", input.SubjectKind, input.SubjectName, input.Role, input.Namespace)}, nil
+}
+
+func RevokePermission(ctx context.Context, req *mcp.CallToolRequest, input RevokePermissionInput) (*mcp.CallToolResult, PermissionOutput, error) {
+	if err := validatePermissionInput(input.SubjectKind, input.Role); err != nil {
+		return nil, PermissionOutput{}, err
+	}
+
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, PermissionOutput{}, err
+	}
+
+	bindings, err := dyn.Resource(roleBindingsGVR).Namespace(input.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, PermissionOutput{}, fmt.Errorf("failed to list role bindings: %v", err)
+	}
+
+	// only bindings created through the dashboard (or this server) are touched,
+	// the admin binding of the project requester stays in place
+	var revoked, unmanaged []string
+	for _, binding := range bindings.Items {
+		subjects := roleBindingSubjects(binding)
+		managed := binding.GetLabels()["opendatahub.io/dashboard"] == "true"
+		for _, subject := range subjects {
+			if subject.Kind != input.SubjectKind || subject.Name != input.SubjectName {
+				continue
+			}
+			if input.Role != "" && subject.Role != input.Role {
+				continue
+			}
+			if !managed || len(subjects) != 1 {
+				unmanaged = append(unmanaged, binding.GetName())
+				continue
+			}
+			err = dyn.Resource(roleBindingsGVR).Namespace(input.Namespace).Delete(ctx, binding.GetName(), metav1.DeleteOptions{})
+			if err != nil {
+				return nil, PermissionOutput{}, fmt.Errorf("failed to delete role binding %s: %v", binding.GetName(), err)
+			}
+			revoked = append(revoked, subject.Role)
+		}
+	}
+
+	msg := fmt.Sprintf("%s %s has no access to project %s to revoke", input.SubjectKind, input.SubjectName, input.Namespace)
+	if len(revoked) > 0 {
+		msg = fmt.Sprintf("Revoked %s access of %s %s to project %s", strings.Join(revoked, ", "), input.SubjectKind, input.SubjectName, input.Namespace)
+	} else if len(unmanaged) > 0 {
+		msg = fmt.Sprintf("%s %s has no access to project %s managed by the dashboard", input.SubjectKind, input.SubjectName, input.Namespace)
+	}
+	if len(unmanaged) > 0 {
+		msg += fmt.Sprintf(". Role bindings not managed by the dashboard were left unchanged: %s", strings.Join(unmanaged, ", "))
+	}
+	return nil, PermissionOutput{Message: msg}, nil
+}
+
+// permissionBindingName builds a readable role binding name, the hash keeps subjects whose
+// names only differ in characters replaced by the sanitizing apart
+func permissionBindingName(kind, name, role string) string {
+	readable := strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if len(readable) > 40 {
+		readable = strings.TrimRight(readable[:40], "-")
+	}
+	hash := sha256.Sum256([]byte(kind + "/" + name))
+	return fmt.Sprintf("dashboard-permissions-%s-%s-%s-%s", strings.ToLower(kind), readable, role, hex.EncodeToString(hash[:])[:8])
+}
+
+func roleBindingSubjects(binding unstructured.Unstructured) []roleBindingSubject {
+	role, _, _ := unstructured.NestedString(binding.Object, "roleRef", "name")
+	subjectsRaw, _, _ := unstructured.NestedSlice(binding.Object, "subjects")
+
+	var subjects []roleBindingSubject
+	for _, s := range subjectsRaw {
+		subjectMap, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		kind, _ := subjectMap["kind"].(string)
+		name, _ := subjectMap["name"].(string)
+		subjects = append(subjects, roleBindingSubject{Kind: kind, Name: name, Role: role})
+	}
+	return subjects
+}
+
+func isProjectRole(role string) bool {
+	for _, r := range projectRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func validatePermissionInput(subjectKind, role string) error {
+	if subjectKind != "User" && subjectKind != "Group" {
+		return fmt.Errorf("subject kind must be User or Group, got: %s", subjectKind)
+	}
+	if role != "" && !isProjectRole(role) {
+		return fmt.Errorf("role must be one of %s, got: %s", strings.Join(projectRoles, ", "), role)
+	}
+	return nil
+}
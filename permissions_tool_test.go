@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func newUnstructuredRoleBinding(name, namespace, role, subjectKind, subjectName string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"roleRef": map[string]interface{}{"kind": "ClusterRole", "name": role},
+		"subjects": []interface{}{
+			map[string]interface{}{"kind": subjectKind, "name": subjectName},
+		},
+	}}
+	u.SetGroupVersionKind(roleBindingsGVR.GroupVersion().WithKind("RoleBinding"))
+	u.SetName(name)
+	u.SetNamespace(namespace)
+	return u
+}
+
+func TestProjectPermissions(t *testing.T) {
+	orig := getDynamicClient
+	defer func() { getDynamicClient = orig }()
+
+	scheme := runtime.NewScheme()
+	client := dynamicfake.NewSimpleDynamicClient(scheme,
+		newUnstructuredRoleBinding("admin", "ns1", "admin", "User", "owner"),
+	)
+	getDynamicClient = func() (dynamic.Interface, error) {
+		return client, nil
+	}
+	ctx := context.Background()
+
+	if _, _, err := GrantPermission(ctx, nil, GrantPermissionInput{Namespace: "ns1", SubjectKind: "User", SubjectName: "alice", Role: "owner"}); err == nil {
+		t.Errorf("expected error for unknown role")
+	}
+
+	_, granted, err := GrantPermission(ctx, nil, GrantPermissionInput{Namespace: "ns1", SubjectKind: "Group", SubjectName: "data-team", Role: "edit"})
+	if err != nil {
+		t.Fatalf("GrantPermission returned error: %v", err)
+	}
+	if granted.Message != "Group data-team was granted edit access to project ns1" {
+		t.Errorf("unexpected message: %q", granted.Message)
+	}
+
+	_, granted, err = GrantPermission(ctx, nil, GrantPermissionInput{Namespace: "ns1", SubjectKind: "Group", SubjectName: "data-team", Role: "edit"})
+	if err != nil {
+		t.Fatalf("GrantPermission returned error: %v", err)
+	}
+	if granted.Message != "Group data-team already has edit access to project ns1" {
+		t.Errorf("unexpected message: %q", granted.Message)
+	}
+
+	_, list, err := ListPermissions(ctx, nil, ListWorkbenchesInput{Namespace: "ns1"})
+	if err != nil {
+		t.Fatalf("ListPermissions returned error: %v", err)
+	}
+	if !strings.Contains(list.Permissions, "- User owner: admin (role binding admin)\n") {
+		t.Errorf("expected owner in output, got: %q", list.Permissions)
+	}
+	if !strings.Contains(list.Permissions, "- Group data-team: edit (role binding dashboard-permissions-group-data-team-edit-") {
+		t.Errorf("expected data-team in output, got: %q", list.Permissions)
+	}
+
+	// subjects whose names sanitize to the same text get their own bindings
+	for _, subject := range []string{"a.b", "a_b"} {
+		if _, _, err := GrantPermission(ctx, nil, GrantPermissionInput{Namespace: "ns1", SubjectKind: "User", SubjectName: subject, Role: "view"}); err != nil {
+			t.Errorf("GrantPermission returned error for %s: %v", subject, err)
+		}
+	}
+	if permissionBindingName("User", "a.b", "view") == permissionBindingName("User", "a_b", "view") {
+		t.Errorf("expected different binding names for a.b and a_b")
+	}
+
+	_, revoked, err := RevokePermission(ctx, nil, RevokePermissionInput{Namespace: "ns1", SubjectKind: "User", SubjectName: "owner"})
+	if err != nil {
+		t.Fatalf("RevokePermission returned error: %v", err)
+	}
+	if revoked.Message != "User owner has no access to project ns1 managed by the dashboard. Role bindings not managed by the dashboard were left unchanged: admin" {
+		t.Errorf("unexpected message: %q", revoked.Message)
+	}
+	if _, err := client.Resource(roleBindingsGVR).Namespace("ns1").Get(ctx, "admin", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected the project admin binding to be left alone: %v", err)
+	}
+
+	_, revoked, err = RevokePermission(ctx, nil, RevokePermissionInput{Namespace: "ns1", SubjectKind: "User", SubjectName: "bob"})
+	if err != nil {
+		t.Fatalf("RevokePermission returned error: %v", err)
+	}
+	if revoked.Message != "User bob has no access to project ns1 to revoke" {
+		t.Errorf("unexpected message: %q", revoked.Message)
+	}
+
+	_, revoked, err = RevokePermission(ctx, nil, RevokePermissionInput{Namespace: "ns1", SubjectKind: "Group", SubjectName: "data-team"})
+	if err != nil {
+		t.Fatalf("RevokePermission returned error: %v", err)
+	}
+	if revoked.Message != "Revoked edit access of Group data-team to project ns1" {
+		t.Errorf("unexpected message: %q", revoked.Message)
+	}
+}
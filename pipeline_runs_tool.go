@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Lists the pipelines of the project's pipeline server together with their versions
+func ListPipelines(ctx context.Context, req *mcp.CallToolRequest, input PipelineServerInput) (*mcp.CallToolResult, ListPipelinesOutput, error) {
+	client, err := newPipelinesClient(ctx, input.Namespace, input.APIURL)
+	if err != nil {
+		return nil, ListPipelinesOutput{}, err
+	}
+
+	pipelines, err := client.listPipelines(ctx)
+	if err != nil {
+		return nil, ListPipelinesOutput{}, fmt.Errorf("failed to list pipelines: %v", err)
+	}
+	if len(pipelines) == 0 {
+		return nil, ListPipelinesOutput{Pipelines: fmt.Sprintf("No pipelines found in project %s", input.Namespace)}, nil
+	}
+
+	msg := ""
+	for _, p := range pipelines {
+		msg += fmt.Sprintf("Pipeline: %s (ID %s)\n", p.DisplayName, p.PipelineID)
+		versions, err := client.listPipelineVersions(ctx, p.PipelineID)
+		if err != nil {
+			return nil, ListPipelinesOutput{}, fmt.Errorf("failed to list versions of pipeline %s: %v", p.DisplayName, err)
+		}
+		for _, version := range versions {
+			msg += fmt.Sprintf("- %s (ID %s, created %s)\n", version.DisplayName, version.PipelineVersionID, version.CreatedAt)
+		}
+	}
+	return nil, ListPipelinesOutput{Pipelines: msg}, nil
+}
+
+// Starts a run of a pipeline version with the given parameters
+func StartPipelineRun(ctx context.Context, req *mcp.CallToolRequest, input StartPipelineRunInput) (*mcp.CallToolResult, PipelineRunOutput, error) {
+	client, err := newPipelinesClient(ctx, input.Namespace, input.APIURL)
+	if err != nil {
+		return nil, PipelineRunOutput{}, err
+	}
+
+	p, versionReference, err := resolvePipelineVersion(ctx, client, input.Namespace, input.Pipeline, input.VersionID)
+	if err != nil {
+		return nil, PipelineRunOutput{}, err
+	}
+
+	run, err := client.createRun(ctx, pipelineRun{
+		DisplayName:              input.RunName,
+		PipelineVersionReference: versionReference,
+		RuntimeConfig:            &pipelineRuntimeConfig{Parameters: input.Parameters},
+	})
+	if err != nil {
+		return nil, PipelineRunOutput{}, fmt.Errorf("failed to start pipeline run: %v", err)
+	}
+
+	return nil, PipelineRunOutput{Message: fmt.Sprintf("Run %s of pipeline %s was succesfully started with ID %s", input.RunName, p.DisplayName, run.RunID)}, nil
+}
+
+// Lists the runs of the project's pipeline server, newest first
+func ListPipelineRuns(ctx context.Context, req *mcp.CallToolRequest, input PipelineServerInput) (*mcp.CallToolResult, ListPipelineRunsOutput, error) {
+	client, err := newPipelinesClient(ctx, input.Namespace, input.APIURL)
+	if err != nil {
+		return nil, ListPipelineRunsOutput{}, err
+	}
+
+	runs, err := client.listRuns(ctx)
+	if err != nil {
+		return nil, ListPipelineRunsOutput{}, fmt.Errorf("failed to list pipeline runs: %v", err)
+	}
+	if len(runs) == 0 {
+		return nil, ListPipelineRunsOutput{Runs: fmt.Sprintf("No pipeline runs found in project %s", input.Namespace)}, nil
+	}
+
+	msg := ""
+	for _, run := range runs {
+		msg += fmt.Sprintf("- %s (ID %s): %s, created %s\n", run.DisplayName, run.RunID, run.State, run.CreatedAt)
+	}
+	return nil, ListPipelineRunsOutput{Runs: msg}, nil
+}
+
+// Describes a pipeline run with its tasks and the step which made it fail
+func GetPipelineRun(ctx context.Context, req *mcp.CallToolRequest, input PipelineRunInput) (*mcp.CallToolResult, PipelineRunOutput, error) {
+	client, err := newPipelinesClient(ctx, input.Namespace, input.APIURL)
+	if err != nil {
+		return nil, PipelineRunOutput{}, err
+	}
+
+	run, err := client.getRun(ctx, input.RunID)
+	if err != nil {
+		return nil, PipelineRunOutput{}, fmt.Errorf("failed to get pipeline run: %v", err)
+	}
+
+	msg := fmt.Sprintf("Run %s (ID %s) is %s\n", run.DisplayName, run.RunID, run.State)
+	if run.PipelineVersionReference != nil {
+		msg += fmt.Sprintf("Pipeline ID: %s, version ID: %s\n", run.PipelineVersionReference.PipelineID, run.PipelineVersionReference.PipelineVersionID)
+	}
+	msg += fmt.Sprintf("Created: %s\n", run.CreatedAt)
+	if run.FinishedAt != "" {
+		msg += fmt.Sprintf("Finished: %s\n", run.FinishedAt)
+	}
+	if run.Error != nil && run.Error.Message != "" {
+		msg += fmt.Sprintf("Error: %s\n", run.Error.Message)
+	}
+
+	if run.RunDetails == nil || len(run.RunDetails.TaskDetails) == 0 {
+		return nil, PipelineRunOutput{Message: msg}, nil
+	}
+	msg += "Tasks:\n"
+	var failed []pipelineTaskDetail
+	for _, task := range run.RunDetails.TaskDetails {
+		msg += fmt.Sprintf("- %s: %s\n", task.DisplayName, task.State)
+		if task.State == "FAILED" {
+			failed = append(failed, task)
+		}
+	}
+	for _, task := range failed {
+		msg += fmt.Sprintf("Failing step: %s", task.DisplayName)
+		if task.Error != nil && task.Error.Message != "" {
+			msg += fmt.Sprintf(" (%s)", task.Error.Message)
+		}
+		msg += "\n"
+	}
+	return nil, PipelineRunOutput{Message: msg}, nil
+}
+
+// Terminates a running pipeline run
+func TerminatePipelineRun(ctx context.Context, req *mcp.CallToolRequest, input PipelineRunInput) (*mcp.CallToolResult, PipelineRunOutput, error) {
+	client, err := newPipelinesClient(ctx, input.Namespace, input.APIURL)
+	if err != nil {
+		return nil, PipelineRunOutput{}, err
+	}
+
+	if err := client.terminateRun(ctx, input.RunID); err != nil {
+		return nil, PipelineRunOutput{}, fmt.Errorf("failed to terminate pipeline run: %v", err)
+	}
+	return nil, PipelineRunOutput{Message: fmt.Sprintf("Run %s is being terminated", input.RunID)}, nil
+}
+
+// Retries a failed or terminated pipeline run from the failed tasks
+func RetryPipelineRun(ctx context.Context, req *mcp.CallToolRequest, input PipelineRunInput) (*mcp.CallToolResult, PipelineRunOutput, error) {
+	client, err := newPipelinesClient(ctx, input.Namespace, input.APIURL)
+	if err != nil {
+		return nil, PipelineRunOutput{}, err
+	}
+
+	if err := client.retryRun(ctx, input.RunID); err != nil {
+		return nil, PipelineRunOutput{}, fmt.Errorf("failed to retry pipeline run: %v", err)
+	}
+	return nil, PipelineRunOutput{Message: fmt.Sprintf("Run %s is being retried", input.RunID)}, nil
+}
+
+// resolvePipelineVersion finds a pipeline by name or ID and the version to run, the newest one when versionID is empty
+func resolvePipelineVersion(ctx context.Context, client *pipelinesClient, namespace, nameOrID, versionID string) (*pipeline, *pipelineVersionReference, error) {
+	p, err := client.findPipeline(ctx, nameOrID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list pipelines: %v", err)
+	}
+	if p == nil {
+		return nil, nil, fmt.Errorf("pipeline %s not found in project %s", nameOrID, namespace)
+	}
+
+	if versionID == "" {
+		versions, err := client.listPipelineVersions(ctx, p.PipelineID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list versions of pipeline %s: %v", p.DisplayName, err)
+		}
+		if len(versions) == 0 {
+			return nil, nil, fmt.Errorf("pipeline %s has no versions", p.DisplayName)
+		}
+		versionID = versions[0].PipelineVersionID
+	}
+	return p, &pipelineVersionReference{PipelineID: p.PipelineID, PipelineVersionID: versionID}, nil
+}
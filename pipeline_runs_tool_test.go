@@ -0,0 +1,290 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+// fakePipelinesServer stands in for the Kubeflow Pipelines v2 REST API keeping everything in memory
+type fakePipelinesServer struct {
+	mu         sync.Mutex
+	pipelines  []pipeline
+	versions   []pipelineVersion
+	runs       []pipelineRun
+	terminated []string
+	retried    []string
+	uploads    []string
+	recurring  []recurringRun
+	// authorization headers of all requests
+	authorizations []string
+}
+
+func (f *fakePipelinesServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.authorizations = append(f.authorizations, r.Header.Get("Authorization"))
+
+	path := strings.TrimPrefix(r.URL.Path, pipelinesAPIPath)
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case r.Method == http.MethodGet && path == "/pipelines":
+		writePipelinesPage(w, r, "pipelines", "next_page_token", f.pipelines)
+	case r.Method == http.MethodGet && len(parts) == 3 && parts[0] == "pipelines" && parts[2] == "versions":
+		// versions are stored oldest first, the API is asked for newest first
+		var items []pipelineVersion
+		for i := len(f.versions) - 1; i >= 0; i-- {
+			if f.versions[i].PipelineID == parts[1] {
+				items = append(items, f.versions[i])
+			}
+		}
+		writePipelinesPage(w, r, "pipeline_versions", "next_page_token", items)
+	case r.Method == http.MethodPost && path == "/pipelines/upload":
+		file, _, err := r.FormFile("uploadfile")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		content, _ := io.ReadAll(file)
+		f.uploads = append(f.uploads, string(content))
+		p := pipeline{PipelineID: fmt.Sprintf("p%d", len(f.pipelines)+1), DisplayName: r.URL.Query().Get("display_name")}
+		f.pipelines = append(f.pipelines, p)
+		f.versions = append(f.versions, pipelineVersion{PipelineID: p.PipelineID, PipelineVersionID: fmt.Sprintf("v%d", len(f.versions)+1), DisplayName: p.DisplayName})
+		_ = json.NewEncoder(w).Encode(p)
+	case r.Method == http.MethodPost && path == "/pipelines/upload_version":
+		file, _, err := r.FormFile("uploadfile")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		content, _ := io.ReadAll(file)
+		f.uploads = append(f.uploads, string(content))
+		version := pipelineVersion{PipelineID: r.URL.Query().Get("pipelineid"), PipelineVersionID: fmt.Sprintf("v%d", len(f.versions)+1), DisplayName: r.URL.Query().Get("display_name")}
+		f.versions = append(f.versions, version)
+		_ = json.NewEncoder(w).Encode(version)
+	case r.Method == http.MethodPost && path == "/runs":
+		var run pipelineRun
+		_ = json.NewDecoder(r.Body).Decode(&run)
+		run.RunID = fmt.Sprintf("run-%d", len(f.runs)+1)
+		run.State = "PENDING"
+		f.runs = append(f.runs, run)
+		_ = json.NewEncoder(w).Encode(run)
+	case r.Method == http.MethodGet && path == "/runs":
+		writePipelinesPage(w, r, "runs", "next_page_token", f.runs)
+	case r.Method == http.MethodGet && len(parts) == 2 && parts[0] == "runs":
+		for _, run := range f.runs {
+			if run.RunID == parts[1] {
+				_ = json.NewEncoder(w).Encode(run)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+	case r.Method == http.MethodPost && len(parts) == 2 && strings.HasSuffix(parts[1], ":terminate"):
+		f.terminated = append(f.terminated, strings.TrimSuffix(parts[1], ":terminate"))
+	case r.Method == http.MethodPost && len(parts) == 2 && strings.HasSuffix(parts[1], ":retry"):
+		f.retried = append(f.retried, strings.TrimSuffix(parts[1], ":retry"))
+	case r.Method == http.MethodPost && path == "/recurringruns":
+		var run recurringRun
+		_ = json.NewDecoder(r.Body).Decode(&run)
+		run.RecurringRunID = fmt.Sprintf("rr-%d", len(f.recurring)+1)
+		run.Status = map[string]string{"ENABLE": "ENABLED", "DISABLE": "DISABLED"}[run.Mode]
+		f.recurring = append(f.recurring, run)
+		_ = json.NewEncoder(w).Encode(run)
+	case r.Method == http.MethodGet && path == "/recurringruns":
+		writePipelinesPage(w, r, "recurringRuns", "nextPageToken", f.recurring)
+	case len(parts) == 2 && parts[0] == "recurringruns":
+		id, action, _ := strings.Cut(parts[1], ":")
+		for i := range f.recurring {
+			if f.recurring[i].RecurringRunID != id {
+				continue
+			}
+			switch {
+			case r.Method == http.MethodPost && action == "enable":
+				f.recurring[i].Status = "ENABLED"
+			case r.Method == http.MethodPost && action == "disable":
+				f.recurring[i].Status = "DISABLED"
+			case r.Method == http.MethodDelete && action == "":
+				f.recurring = append(f.recurring[:i], f.recurring[i+1:]...)
+			default:
+				w.WriteHeader(http.StatusBadRequest)
+			}
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// writePipelinesPage serves items one per page so clients have to follow the page token
+func writePipelinesPage[T any](w http.ResponseWriter, r *http.Request, itemsField, tokenField string, items []T) {
+	offset, _ := strconv.Atoi(r.URL.Query().Get("page_token"))
+	page := map[string]interface{}{itemsField: []T{}}
+	if offset < len(items) {
+		page[itemsField] = items[offset : offset+1]
+	}
+	if offset+1 < len(items) {
+		page[tokenField] = strconv.Itoa(offset + 1)
+	}
+	_ = json.NewEncoder(w).Encode(page)
+}
+
+func newFakePipelinesServer(t *testing.T, fake *fakePipelinesServer) *httptest.Server {
+	origToken := getClusterToken
+	getClusterToken = func() (string, error) { return "", fmt.Errorf("no kubeconfig") }
+	server := httptest.NewServer(fake)
+	t.Cleanup(func() {
+		server.Close()
+		getClusterToken = origToken
+	})
+	return server
+}
+
+func TestPipelineRuns(t *testing.T) {
+	failedRun := pipelineRun{
+		RunID:       "run-1",
+		DisplayName: "nightly",
+		State:       "FAILED",
+		CreatedAt:   "2026-10-18T02:00:00Z",
+		PipelineVersionReference: &pipelineVersionReference{
+			PipelineID:        "p1",
+			PipelineVersionID: "v1",
+		},
+		RunDetails: &pipelineRunDetails{TaskDetails: []pipelineTaskDetail{
+			{TaskID: "t1", DisplayName: "load-data", State: "SUCCEEDED"},
+			{TaskID: "t2", DisplayName: "train-model", State: "FAILED", Error: &pipelineStatus{Message: "OOMKilled"}},
+		}},
+	}
+
+	fake := &fakePipelinesServer{
+		pipelines: []pipeline{{PipelineID: "p1", DisplayName: "training"}},
+		versions: []pipelineVersion{
+			{PipelineID: "p1", PipelineVersionID: "v1", DisplayName: "training v1", CreatedAt: "2026-10-01T00:00:00Z"},
+			{PipelineID: "p1", PipelineVersionID: "v2", DisplayName: "training v2", CreatedAt: "2026-10-02T00:00:00Z"},
+		},
+		runs: []pipelineRun{failedRun},
+	}
+	server := newFakePipelinesServer(t, fake)
+	ctx := context.Background()
+
+	_, pipelines, err := ListPipelines(ctx, nil, PipelineServerInput{Namespace: "ns1", APIURL: server.URL})
+	if err != nil {
+		t.Fatalf("ListPipelines returned error: %v", err)
+	}
+	if pipelines.Pipelines != "Pipeline: training (ID p1)\n- training v2 (ID v2, created 2026-10-02T00:00:00Z)\n- training v1 (ID v1, created 2026-10-01T00:00:00Z)\n" {
+		t.Errorf("unexpected pipelines: %q", pipelines.Pipelines)
+	}
+
+	if _, _, err := StartPipelineRun(ctx, nil, StartPipelineRunInput{Namespace: "ns1", APIURL: server.URL, Pipeline: "missing", RunName: "r"}); err == nil {
+		t.Errorf("expected error for missing pipeline")
+	}
+	_, out, err := StartPipelineRun(ctx, nil, StartPipelineRunInput{
+		Namespace:  "ns1",
+		APIURL:     server.URL,
+		Pipeline:   "training",
+		RunName:    "manual",
+		Parameters: map[string]interface{}{"epochs": 3},
+	})
+	if err != nil {
+		t.Fatalf("StartPipelineRun returned error: %v", err)
+	}
+	if out.Message != "Run manual of pipeline training was succesfully started with ID run-2" {
+		t.Errorf("unexpected message: %q", out.Message)
+	}
+	started := fake.runs[1]
+	if started.PipelineVersionReference.PipelineVersionID != "v2" || started.RuntimeConfig.Parameters["epochs"] != float64(3) {
+		t.Errorf("expected run of newest version with parameters, got: %+v %+v", started.PipelineVersionReference, started.RuntimeConfig)
+	}
+
+	_, runs, err := ListPipelineRuns(ctx, nil, PipelineServerInput{Namespace: "ns1", APIURL: server.URL})
+	if err != nil {
+		t.Fatalf("ListPipelineRuns returned error: %v", err)
+	}
+	if !strings.Contains(runs.Runs, "- nightly (ID run-1): FAILED, created 2026-10-18T02:00:00Z\n") || !strings.Contains(runs.Runs, "- manual (ID run-2): PENDING") {
+		t.Errorf("unexpected runs: %q", runs.Runs)
+	}
+
+	_, out, err = GetPipelineRun(ctx, nil, PipelineRunInput{Namespace: "ns1", APIURL: server.URL, RunID: "run-1"})
+	if err != nil {
+		t.Fatalf("GetPipelineRun returned error: %v", err)
+	}
+	for _, expected := range []string{"Run nightly (ID run-1) is FAILED\n", "- load-data: SUCCEEDED\n", "Failing step: train-model (OOMKilled)\n"} {
+		if !strings.Contains(out.Message, expected) {
+			t.Errorf("expected %q in output, got: %q", expected, out.Message)
+		}
+	}
+
+	if _, _, err := TerminatePipelineRun(ctx, nil, PipelineRunInput{Namespace: "ns1", APIURL: server.URL, RunID: "run-2"}); err != nil {
+		t.Fatalf("TerminatePipelineRun returned error: %v", err)
+	}
+	if _, _, err := RetryPipelineRun(ctx, nil, PipelineRunInput{Namespace: "ns1", APIURL: server.URL, RunID: "run-1"}); err != nil {
+		t.Fatalf("RetryPipelineRun returned error: %v", err)
+	}
+	if len(fake.terminated) != 1 || fake.terminated[0] != "run-2" || len(fake.retried) != 1 || fake.retried[0] != "run-1" {
+		t.Errorf("unexpected terminated %v and retried %v runs", fake.terminated, fake.retried)
+	}
+}
+
+func TestPipelinesClientToken(t *testing.T) {
+	fake := &fakePipelinesServer{}
+	server := newFakePipelinesServer(t, fake)
+	getClusterToken = func() (string, error) { return "kube-token", nil }
+
+	origDynamic := getDynamicClient
+	defer func() { getDynamicClient = origDynamic }()
+	pipelineServer := newUnstructuredPipelineServer("dspa", "ns1", true)
+	_ = unstructured.SetNestedField(pipelineServer.Object, server.URL, "status", "components", "apiServer", "externalUrl")
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), pipelineServer)
+	getDynamicClient = func() (dynamic.Interface, error) {
+		return client, nil
+	}
+	ctx := context.Background()
+
+	if _, _, err := ListPipelines(ctx, nil, PipelineServerInput{Namespace: "ns1"}); err != nil {
+		t.Fatalf("ListPipelines returned error: %v", err)
+	}
+	if _, _, err := ListPipelines(ctx, nil, PipelineServerInput{Namespace: "ns1", APIURL: server.URL}); err != nil {
+		t.Fatalf("ListPipelines returned error: %v", err)
+	}
+	if len(fake.authorizations) != 2 || fake.authorizations[0] != "Bearer kube-token" || fake.authorizations[1] != "" {
+		t.Errorf("expected the token only for the pipeline server URL, got: %q", fake.authorizations)
+	}
+}
+
+func TestPipelinesClientPaging(t *testing.T) {
+	// the fake server returns one item per page
+	fake := &fakePipelinesServer{
+		pipelines: []pipeline{{PipelineID: "p1", DisplayName: "prepare"}, {PipelineID: "p2", DisplayName: "evaluate"}, {PipelineID: "p3", DisplayName: "training"}},
+		runs:      []pipelineRun{{RunID: "run-1"}, {RunID: "run-2"}, {RunID: "run-3"}},
+		recurring: []recurringRun{{RecurringRunID: "rr-1"}, {RecurringRunID: "rr-2"}},
+	}
+	server := newFakePipelinesServer(t, fake)
+	ctx := context.Background()
+	client, err := newPipelinesClient(ctx, "ns1", server.URL)
+	if err != nil {
+		t.Fatalf("newPipelinesClient returned error: %v", err)
+	}
+
+	found, err := client.findPipeline(ctx, "training")
+	if err != nil || found == nil || found.PipelineID != "p3" {
+		t.Errorf("expected pipeline training from the last page, got %v, %v", found, err)
+	}
+	if runs, err := client.listRuns(ctx); err != nil || len(runs) != 3 {
+		t.Errorf("expected 3 runs, got %d, %v", len(runs), err)
+	}
+	if recurring, err := client.listRecurringRuns(ctx); err != nil || len(recurring) != 2 {
+		t.Errorf("expected 2 recurring runs, got %d, %v", len(recurring), err)
+	}
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+// conditions reported by the data science pipelines operator for each component
+var pipelineServerConditions = []string{"DatabaseAvailable", "ObjectStoreAvailable", "APIServerReady", "PersistenceAgentReady", "ScheduledWorkflowReady"}
+
+// Reports whether a project has a pipeline server and how ready its components are
+func GetPipelineServer(ctx context.Context, req *mcp.CallToolRequest, input ListWorkbenchesInput) (*mcp.CallToolResult, PipelineServerOutput, error) {
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, PipelineServerOutput{}, err
+	}
+
+	server, err := getPipelineServer(ctx, dyn, input.Namespace)
+	if err != nil {
+		return nil, PipelineServerOutput{}, err
+	}
+	if server == nil {
+		return nil, PipelineServerOutput{Message: fmt.Sprintf("Project %s has no pipeline server", input.Namespace)}, nil
+	}
+
+	msg := fmt.Sprintf("Pipeline server %s is %s\n", server.GetName(), pipelineServerState(*server))
+	conditions := pipelineServerConditionsByType(*server)
+	for _, conditionType := range pipelineServerConditions {
+		if condition, ok := conditions[conditionType]; ok {
+			msg += fmt.Sprintf("- %s: %s", conditionType, condition["status"])
+			if message, _ := condition["message"].(string); message != "" && condition["status"] != "True" {
+				msg += fmt.Sprintf(" (%s)", message)
+			}
+			msg += "\n"
+		}
+	}
+	if apiURL := pipelineServerURL(*server); apiURL != "" {
+		msg += fmt.Sprintf("API URL: %s\n", apiURL)
+	}
+	if secretName, _, _ := unstructured.NestedString(server.Object, "spec", "objectStorage", "externalStorage", "s3CredentialsSecret", "secretName"); secretName != "" {
+		msg += fmt.Sprintf("Connection: %s\n", secretName)
+	}
+	return nil, PipelineServerOutput{Message: msg}, nil
+}
+
+// Creates a DataSciencePipelinesApplication storing artifacts through an existing s3 connection
+func CreatePipelineServer(ctx context.Context, req *mcp.CallToolRequest, input CreatePipelineServerInput) (*mcp.CallToolResult, PipelineServerOutput, error) {
+	name := input.Name
+	if name == "" {
+		name = "dspa"
+	}
+
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, PipelineServerOutput{}, err
+	}
+
+	existing, err := getPipelineServer(ctx, dyn, input.Namespace)
+	if err != nil {
+		return nil, PipelineServerOutput{}, err
+	}
+	if existing != nil {
+		return nil, PipelineServerOutput{Message: fmt.Sprintf("Project %s already has pipeline server %s", input.Namespace, existing.GetName())}, nil
+	}
+
+	secret, err := getConnection(ctx, dyn, input.Namespace, input.ConnectionName)
+	if err != nil {
+		return nil, PipelineServerOutput{}, err
+	}
+	if connectionType(*secret) != "s3" {
+		return nil, PipelineServerOutput{}, fmt.Errorf("connection %s is not an s3 connection", input.ConnectionName)
+	}
+	values, err := connectionValues(*secret)
+	if err != nil {
+		return nil, PipelineServerOutput{}, err
+	}
+	endpoint := values["AWS_S3_ENDPOINT"]
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil || endpointURL.Host == "" {
+		return nil, PipelineServerOutput{}, fmt.Errorf("connection %s has invalid endpoint %s", input.ConnectionName, values["AWS_S3_ENDPOINT"])
+	}
+	if values["AWS_S3_BUCKET"] == "" {
+		return nil, PipelineServerOutput{}, fmt.Errorf("connection %s has no bucket", input.ConnectionName)
+	}
+	region := values["AWS_DEFAULT_REGION"]
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	server := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "datasciencepipelinesapplications.opendatahub.io/v1",
+			"kind":       "DataSciencePipelinesApplication",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": input.Namespace,
+			},
+			"spec": map[string]interface{}{
+				"dspVersion": "v2",
+				"apiServer": map[string]interface{}{
+					"deploy":               true,
+					"enableSamplePipeline": false,
+				},
+				"database": map[string]interface{}{
+					"mariaDB": map[string]interface{}{
+						"deploy":         true,
+						"pipelineDBName": "mlpipeline",
+						"pvcSize":        "10Gi",
+						"username":       "mlpipeline",
+					},
+				},
+				"objectStorage": map[string]interface{}{
+					"externalStorage": map[string]interface{}{
+						"host":   endpointURL.Host,
+						"scheme": endpointURL.Scheme,
+						"bucket": values["AWS_S3_BUCKET"],
+						"region": region,
+						"s3CredentialsSecret": map[string]interface{}{
+							"secretName": input.ConnectionName,
+							"accessKey":  "AWS_ACCESS_KEY_ID",
+							"secretKey":  "AWS_SECRET_ACCESS_KEY",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	_, err = dyn.Resource(pipelineServersGVR).Namespace(input.Namespace).Create(ctx, server, metav1.CreateOptions{})
+	if err != nil {
+		return nil, PipelineServerOutput{}, fmt.Errorf("failed to create pipeline server: %v", err)
+	}
+
+	return nil, PipelineServerOutput{Message: fmt.Sprintf("Pipeline server %s was succesfully created in project %s, it takes a few minutes to become ready", name, input.Namespace)}, nil
+}
+
+// getPipelineServer returns the pipeline server of the namespace, nil when there is none
+func getPipelineServer(ctx context.Context, dyn dynamic.Interface, namespace string) (*unstructured.Unstructured, error) {
+	servers, err := dyn.Resource(pipelineServersGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pipeline servers: %v", err)
+	}
+	if len(servers.Items) == 0 {
+		return nil, nil
+	}
+	return &servers.Items[0], nil
+}
+
+func pipelineServerState(server unstructured.Unstructured) string {
+	ready, ok := pipelineServerConditionsByType(server)["Ready"]
+	if !ok {
+		return "starting"
+	}
+	if ready["status"] == "True" {
+		return "ready"
+	}
+	if reason, _ := ready["reason"].(string); reason != "" {
+		return fmt.Sprintf("not ready (%s)", reason)
+	}
+	return "not ready"
+}
+
+func pipelineServerConditionsByType(server unstructured.Unstructured) map[string]map[string]interface{} {
+	conditionsRaw, _, _ := unstructured.NestedSlice(server.Object, "status", "conditions")
+	conditions := map[string]map[string]interface{}{}
+	for _, c := range conditionsRaw {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if conditionType, _ := condition["type"].(string); conditionType != "" {
+			conditions[conditionType] = condition
+		}
+	}
+	return conditions
+}
+
+// pipelineServerURL returns the external URL of the pipeline API server, the internal one when there is no route
+func pipelineServerURL(server unstructured.Unstructured) string {
+	if externalURL, _, _ := unstructured.NestedString(server.Object, "status", "components", "apiServer", "externalUrl"); externalURL != "" {
+		return externalURL
+	}
+	internalURL, _, _ := unstructured.NestedString(server.Object, "status", "components", "apiServer", "url")
+	return internalURL
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func newUnstructuredPipelineServer(name, namespace string, ready bool) *unstructured.Unstructured {
+	status := "False"
+	if ready {
+		status = "True"
+	}
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "APIServerReady", "status": status, "message": "deployment is progressing"},
+				map[string]interface{}{"type": "Ready", "status": status, "reason": "MinimumReplicasUnavailable"},
+			},
+			"components": map[string]interface{}{
+				"apiServer": map[string]interface{}{
+					"url":         "https://ds-pipeline-" + name + "." + namespace + ".svc.cluster.local:8443",
+					"externalUrl": "https://ds-pipeline-" + name + "-" + namespace + ".apps.example.com",
+				},
+			},
+		},
+	}}
+	u.SetGroupVersionKind(pipelineServersGVR.GroupVersion().WithKind("DataSciencePipelinesApplication"))
+	u.SetName(name)
+	u.SetNamespace(namespace)
+	return u
+}
+
+func TestPipelineServer(t *testing.T) {
+	orig := getDynamicClient
+	defer func() { getDynamicClient = orig }()
+
+	scheme := runtime.NewScheme()
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme,
+		map[schema.GroupVersionResource]string{pipelineServersGVR: "DataSciencePipelinesApplicationList"},
+		newUnstructuredPipelineServer("dspa", "ns1", false),
+		newUnstructuredConnection("artifacts", "ns2", "s3", map[string]string{
+			"AWS_S3_ENDPOINT": "http://minio.minio.svc:9000",
+			"AWS_S3_BUCKET":   "pipelines",
+		}),
+		newUnstructuredConnection("registry", "ns2", "oci-v1", map[string]string{}),
+	)
+	getDynamicClient = func() (dynamic.Interface, error) {
+		return client, nil
+	}
+	ctx := context.Background()
+
+	_, out, err := GetPipelineServer(ctx, nil, ListWorkbenchesInput{Namespace: "ns1"})
+	if err != nil {
+		t.Fatalf("GetPipelineServer returned error: %v", err)
+	}
+	for _, expected := range []string{
+		"Pipeline server dspa is not ready (MinimumReplicasUnavailable)\n",
+		"- APIServerReady: False (deployment is progressing)\n",
+		"API URL: https://ds-pipeline-dspa-ns1.apps.example.com\n",
+	} {
+		if !strings.Contains(out.Message, expected) {
+			t.Errorf("expected %q in output, got: %q", expected, out.Message)
+		}
+	}
+
+	_, out, err = GetPipelineServer(ctx, nil, ListWorkbenchesInput{Namespace: "ns2"})
+	if err != nil {
+		t.Fatalf("GetPipelineServer returned error: %v", err)
+	}
+	if out.Message != "Project ns2 has no pipeline server" {
+		t.Errorf("unexpected message: %q", out.Message)
+	}
+
+	if _, _, err := CreatePipelineServer(ctx, nil, CreatePipelineServerInput{Namespace: "ns2", ConnectionName: "registry"}); err == nil {
+		t.Errorf("expected error for a connection which is not s3")
+	}
+
+	_, out, err = CreatePipelineServer(ctx, nil, CreatePipelineServerInput{Namespace: "ns2", ConnectionName: "artifacts"})
+	if err != nil {
+		t.Fatalf("CreatePipelineServer returned error: %v", err)
+	}
+	if !strings.HasPrefix(out.Message, "Pipeline server dspa was succesfully created in project ns2") {
+		t.Errorf("unexpected message: %q", out.Message)
+	}
+	created, err := client.Resource(pipelineServersGVR).Namespace("ns2").Get(ctx, "dspa", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected pipeline server to be created: %v", err)
+	}
+	storage, _, _ := unstructured.NestedMap(created.Object, "spec", "objectStorage", "externalStorage")
+	if storage["host"] != "minio.minio.svc:9000" || storage["scheme"] != "http" || storage["bucket"] != "pipelines" || storage["region"] != "us-east-1" {
+		t.Errorf("unexpected object storage: %v", storage)
+	}
+
+	_, out, err = CreatePipelineServer(ctx, nil, CreatePipelineServerInput{Namespace: "ns1", ConnectionName: "artifacts"})
+	if err != nil {
+		t.Fatalf("CreatePipelineServer returned error: %v", err)
+	}
+	if out.Message != "Project ns1 already has pipeline server dspa" {
+		t.Errorf("unexpected message: %q", out.Message)
+	}
+}
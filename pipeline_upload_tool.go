@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// separates the pipeline spec from the optional platform spec in compiled pipelines
+var yamlDocumentSeparator = regexp.MustCompile(`(?m)^---\s*$`)
+
+var yamlErrorLine = regexp.MustCompile(`line \d+`)
+
+// Uploads a compiled pipeline as a new pipeline, or as a new version when the pipeline already exists
+func UploadPipeline(ctx context.Context, req *mcp.CallToolRequest, input UploadPipelineInput) (*mcp.CallToolResult, PipelineRunOutput, error) {
+	content := []byte(input.PipelineYAML)
+	if input.Workbench != "" {
+		if input.Path == "" {
+			return nil, PipelineRunOutput{}, fmt.Errorf("path is required when reading the pipeline from a workbench")
+		}
+		dyn, err := getDynamicClient()
+		if err != nil {
+			return nil, PipelineRunOutput{}, err
+		}
+		notebook, err := dyn.Resource(workbenchesGVR).Namespace(input.Namespace).Get(ctx, input.Workbench, metav1.GetOptions{})
+		if err != nil {
+			return nil, PipelineRunOutput{}, fmt.Errorf("failed to get workbench %s: %v", input.Workbench, err)
+		}
+		filePath, err := workbenchFilePath(ctx, *notebook, input.Path)
+		if err != nil {
+			return nil, PipelineRunOutput{}, err
+		}
+		content, err = readWorkbenchFile(ctx, input.Namespace, input.Workbench, filePath)
+		if err != nil {
+			return nil, PipelineRunOutput{}, err
+		}
+	}
+	if len(strings.TrimSpace(string(content))) == 0 {
+		return nil, PipelineRunOutput{}, fmt.Errorf("either pipelineYAML or workbench and path are required")
+	}
+	if err := validatePipelineSpec(content); err != nil {
+		return nil, PipelineRunOutput{}, err
+	}
+
+	client, err := newPipelinesClient(ctx, input.Namespace, input.APIURL)
+	if err != nil {
+		return nil, PipelineRunOutput{}, err
+	}
+
+	existing, err := client.findPipeline(ctx, input.PipelineName)
+	if err != nil {
+		return nil, PipelineRunOutput{}, fmt.Errorf("failed to list pipelines: %v", err)
+	}
+
+	if existing == nil {
+		created, err := client.uploadPipeline(ctx, input.PipelineName, input.Description, content)
+		if err != nil {
+			return nil, PipelineRunOutput{}, fmt.Errorf("failed to upload pipeline: %v", err)
+		}
+		// uploading a pipeline also creates its first version
+		versions, err := client.listPipelineVersions(ctx, created.PipelineID)
+		if err != nil {
+			return nil, PipelineRunOutput{}, fmt.Errorf("failed to list versions of pipeline %s: %v", input.PipelineName, err)
+		}
+		msg := fmt.Sprintf("Pipeline %s was succesfully uploaded with ID %s", input.PipelineName, created.PipelineID)
+		if len(versions) > 0 {
+			msg += fmt.Sprintf(", version ID %s", versions[0].PipelineVersionID)
+		}
+		return nil, PipelineRunOutput{Message: msg}, nil
+	}
+
+	versionName := input.VersionName
+	if versionName == "" {
+		versionName = fmt.Sprintf("%s-%s", input.PipelineName, time.Now().UTC().Format("20060102-150405"))
+	}
+	version, err := client.uploadPipelineVersion(ctx, existing.PipelineID, versionName, input.Description, content)
+	if err != nil {
+		return nil, PipelineRunOutput{}, fmt.Errorf("failed to upload pipeline version: %v", err)
+	}
+	return nil, PipelineRunOutput{Message: fmt.Sprintf("Version %s of pipeline %s was succesfully uploaded, pipeline ID %s, version ID %s", versionName, input.PipelineName, existing.PipelineID, version.PipelineVersionID)}, nil
+}
+
+// validatePipelineSpec checks that content is a compiled KFP IR pipeline whose tasks
+// reference existing components and whose components reference existing executors
+func validatePipelineSpec(content []byte) error {
+	var spec map[string]interface{}
+	for _, doc := range yamlDocumentSeparator.Split(string(content), -1) {
+		if strings.TrimSpace(doc) == "" {
+			continue
+		}
+		// parser errors can quote the content, only the line is reported back
+		if err := yaml.Unmarshal([]byte(doc), &spec); err != nil {
+			if line := yamlErrorLine.FindString(err.Error()); line != "" {
+				return fmt.Errorf("pipeline is not valid YAML at %s", line)
+			}
+			return fmt.Errorf("pipeline is not valid YAML")
+		}
+		break
+	}
+	if spec == nil {
+		return fmt.Errorf("pipeline YAML is empty")
+	}
+
+	var problems []string
+	if name, _ := nestedValue(spec, "pipelineInfo", "name").(string); name == "" {
+		problems = append(problems, "pipelineInfo.name is missing")
+	}
+	if schemaVersion, _ := spec["schemaVersion"].(string); schemaVersion == "" {
+		problems = append(problems, "schemaVersion is missing")
+	}
+	components, _ := spec["components"].(map[string]interface{})
+	executors, _ := nestedValue(spec, "deploymentSpec", "executors").(map[string]interface{})
+
+	rootTasks, _ := nestedValue(spec, "root", "dag", "tasks").(map[string]interface{})
+	if len(rootTasks) == 0 {
+		problems = append(problems, "root.dag.tasks is missing, this does not look like a compiled KFP v2 pipeline")
+	}
+	problems = append(problems, pipelineTaskProblems("root", rootTasks, components)...)
+
+	componentNames := make([]string, 0, len(components))
+	for name := range components {
+		componentNames = append(componentNames, name)
+	}
+	sort.Strings(componentNames)
+	for _, name := range componentNames {
+		component, _ := components[name].(map[string]interface{})
+		if executorLabel, _ := component["executorLabel"].(string); executorLabel != "" {
+			if _, ok := executors[executorLabel]; !ok {
+				problems = append(problems, fmt.Sprintf("component %s references missing executor %s", name, executorLabel))
+			}
+		}
+		tasks, _ := nestedValue(component, "dag", "tasks").(map[string]interface{})
+		problems = append(problems, pipelineTaskProblems(name, tasks, components)...)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid pipeline: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func pipelineTaskProblems(parent string, tasks, components map[string]interface{}) []string {
+	taskNames := make([]string, 0, len(tasks))
+	for name := range tasks {
+		taskNames = append(taskNames, name)
+	}
+	sort.Strings(taskNames)
+
+	var problems []string
+	for _, name := range taskNames {
+		task, _ := tasks[name].(map[string]interface{})
+		componentName, _ := nestedValue(task, "componentRef", "name").(string)
+		if componentName == "" {
+			problems = append(problems, fmt.Sprintf("task %s of %s has no componentRef", name, parent))
+			continue
+		}
+		if _, ok := components[componentName]; !ok {
+			problems = append(problems, fmt.Sprintf("task %s of %s references missing component %s", name, parent, componentName))
+		}
+	}
+	return problems
+}
+
+// nestedValue walks maps decoded from YAML, returning nil when a key is missing
+func nestedValue(obj map[string]interface{}, keys ...string) interface{} {
+	var value interface{} = obj
+	for _, key := range keys {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = m[key]
+	}
+	return value
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+const testPipelineYAML = `# PIPELINE DEFINITION
+# Name: training
+components:
+  comp-train:
+    executorLabel: exec-train
+deploymentSpec:
+  executors:
+    exec-train:
+      container:
+        image: quay.io/modh/runtime-images:latest
+pipelineInfo:
+  name: training
+root:
+  dag:
+    tasks:
+      train:
+        componentRef:
+          name: comp-train
+schemaVersion: 2.1.0
+sdkVersion: kfp-2.9.0
+---
+platforms:
+  kubernetes:
+    deploymentSpec:
+      executors: {}
+`
+
+func TestValidatePipelineSpec(t *testing.T) {
+	if err := validatePipelineSpec([]byte(testPipelineYAML)); err != nil {
+		t.Errorf("expected valid pipeline, got: %v", err)
+	}
+
+	broken := strings.Replace(testPipelineYAML, "name: comp-train", "name: comp-missing", 1)
+	broken = strings.Replace(broken, "executorLabel: exec-train", "executorLabel: exec-missing", 1)
+	err := validatePipelineSpec([]byte(broken))
+	if err == nil {
+		t.Fatalf("expected error for broken references")
+	}
+	for _, expected := range []string{"task train of root references missing component comp-missing", "component comp-train references missing executor exec-missing"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected %q in error, got: %v", expected, err)
+		}
+	}
+
+	if err := validatePipelineSpec([]byte("apiVersion: argoproj.io/v1alpha1\nkind: Workflow\n")); err == nil || !strings.Contains(err.Error(), "root.dag.tasks is missing") {
+		t.Errorf("expected error for a non KFP v2 YAML, got: %v", err)
+	}
+	if err := validatePipelineSpec([]byte("pipelineInfo: [")); err == nil {
+		t.Errorf("expected error for invalid YAML")
+	}
+	if err := validatePipelineSpec([]byte("token-abc123")); err == nil || strings.Contains(err.Error(), "token-abc123") {
+		t.Errorf("expected error without the file content, got: %v", err)
+	}
+}
+
+// newPipelineWorkbench returns a workbench with its home PVC and a data PVC mounted at /mnt/data
+func newPipelineWorkbench(name, namespace string) *unstructured.Unstructured {
+	nb := newUnstructuredWorkbench(name, namespace)
+	_ = unstructured.SetNestedSlice(nb.Object, []interface{}{
+		map[string]interface{}{
+			"name": name,
+			"volumeMounts": []interface{}{
+				map[string]interface{}{"name": "storage-volume", "mountPath": "/opt/app-root/src/"},
+				map[string]interface{}{"name": "storage-1", "mountPath": "/mnt/data"},
+				map[string]interface{}{"name": "shm", "mountPath": "/dev/shm"},
+			},
+		},
+	}, "spec", "template", "spec", "containers")
+	_ = unstructured.SetNestedSlice(nb.Object, []interface{}{
+		storageVolume("storage-volume", name),
+		storageVolume("storage-1", "data"),
+		map[string]interface{}{"name": "shm", "emptyDir": map[string]interface{}{"medium": "Memory"}},
+	}, "spec", "template", "spec", "volumes")
+	return nb
+}
+
+func TestWorkbenchPath(t *testing.T) {
+	nb := *newPipelineWorkbench("wb1", "ns1")
+	for p, expected := range map[string]string{
+		"pipelines/training.yaml":                   "/opt/app-root/src/pipelines/training.yaml",
+		"./pipelines/../training.yaml":              "/opt/app-root/src/training.yaml",
+		"/opt/app-root/src/pipelines/training.yaml": "/opt/app-root/src/pipelines/training.yaml",
+		"/mnt/data/training.yaml":                   "/mnt/data/training.yaml",
+	} {
+		resolved, err := workbenchPath(nb, p)
+		if err != nil || resolved != expected {
+			t.Errorf("expected %s to resolve to %s, got %q, %v", p, expected, resolved, err)
+		}
+	}
+	for _, p := range []string{
+		"../../../../var/run/secrets/kubernetes.io/serviceaccount/token",
+		"/var/run/secrets/kubernetes.io/serviceaccount/token",
+		"/opt/app-root/src/../etc/passwd",
+		"/opt/app-root/srcfoo/training.yaml",
+		"/dev/shm/training.yaml",
+		"/opt/app-root/src",
+		"/",
+	} {
+		if resolved, err := workbenchPath(nb, p); err == nil {
+			t.Errorf("expected error for %s, got %s", p, resolved)
+		}
+	}
+}
+
+// fakeResolveWorkbenchPath resolves paths like realpath in a workbench with ~/p.yaml linking to the service account token
+func fakeResolveWorkbenchPath(ctx context.Context, namespace, workbench, path string) (string, error) {
+	if path == "/opt/app-root/src/p.yaml" {
+		return "/var/run/secrets/kubernetes.io/serviceaccount/token", nil
+	}
+	return path, nil
+}
+
+func TestWorkbenchFilePath(t *testing.T) {
+	origResolve := resolveWorkbenchPath
+	defer func() { resolveWorkbenchPath = origResolve }()
+	resolveWorkbenchPath = fakeResolveWorkbenchPath
+
+	nb := *newPipelineWorkbench("wb1", "ns1")
+	ctx := context.Background()
+	if resolved, err := workbenchFilePath(ctx, nb, "pipelines/training.yaml"); err != nil || resolved != "/opt/app-root/src/pipelines/training.yaml" {
+		t.Errorf("unexpected resolved path %q, %v", resolved, err)
+	}
+	if resolved, err := workbenchFilePath(ctx, nb, "p.yaml"); err == nil {
+		t.Errorf("expected error for a symlink pointing outside of the workbench storage, got %s", resolved)
+	}
+	if resolved, err := workbenchFilePath(ctx, nb, "../../etc/passwd"); err == nil {
+		t.Errorf("expected error for a path outside of the workbench storage, got %s", resolved)
+	}
+}
+
+func TestUploadPipeline(t *testing.T) {
+	origRead := readWorkbenchFile
+	origResolve := resolveWorkbenchPath
+	defer func() {
+		readWorkbenchFile = origRead
+		resolveWorkbenchPath = origResolve
+	}()
+	resolveWorkbenchPath = fakeResolveWorkbenchPath
+	readWorkbenchFile = func(ctx context.Context, namespace, workbench, path string) ([]byte, error) {
+		if namespace != "ns1" || workbench != "wb1" || path != "/opt/app-root/src/pipelines/training.yaml" {
+			return nil, fmt.Errorf("unexpected file %s/%s:%s", namespace, workbench, path)
+		}
+		return []byte(testPipelineYAML), nil
+	}
+
+	origDyn := getDynamicClient
+	defer func() { getDynamicClient = origDyn }()
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), newPipelineWorkbench("wb1", "ns1"))
+	getDynamicClient = func() (dynamic.Interface, error) {
+		return client, nil
+	}
+
+	fake := &fakePipelinesServer{}
+	server := newFakePipelinesServer(t, fake)
+	ctx := context.Background()
+
+	if _, _, err := UploadPipeline(ctx, nil, UploadPipelineInput{Namespace: "ns1", APIURL: server.URL, PipelineName: "training"}); err == nil {
+		t.Errorf("expected error without pipeline YAML")
+	}
+	if _, _, err := UploadPipeline(ctx, nil, UploadPipelineInput{Namespace: "ns1", APIURL: server.URL, PipelineName: "training", PipelineYAML: "kind: Workflow"}); err == nil {
+		t.Errorf("expected error for invalid pipeline")
+	}
+
+	_, out, err := UploadPipeline(ctx, nil, UploadPipelineInput{Namespace: "ns1", APIURL: server.URL, PipelineName: "training", PipelineYAML: testPipelineYAML})
+	if err != nil {
+		t.Fatalf("UploadPipeline returned error: %v", err)
+	}
+	if out.Message != "Pipeline training was succesfully uploaded with ID p1, version ID v1" {
+		t.Errorf("unexpected message: %q", out.Message)
+	}
+
+	_, out, err = UploadPipeline(ctx, nil, UploadPipelineInput{Namespace: "ns1", APIURL: server.URL, PipelineName: "training", VersionName: "training-v2", Workbench: "wb1", Path: "pipelines/training.yaml"})
+	if err != nil {
+		t.Fatalf("UploadPipeline returned error: %v", err)
+	}
+	if out.Message != "Version training-v2 of pipeline training was succesfully uploaded, pipeline ID p1, version ID v2" {
+		t.Errorf("unexpected message: %q", out.Message)
+	}
+	if _, _, err := UploadPipeline(ctx, nil, UploadPipelineInput{Namespace: "ns1", APIURL: server.URL, PipelineName: "training", Workbench: "wb1", Path: "../../../var/run/secrets/kubernetes.io/serviceaccount/token"}); err == nil {
+		t.Errorf("expected error for a path outside of the workbench storage")
+	}
+	if _, _, err := UploadPipeline(ctx, nil, UploadPipelineInput{Namespace: "ns1", APIURL: server.URL, PipelineName: "training", Workbench: "wb1", Path: "p.yaml"}); err == nil {
+		t.Errorf("expected error for a symlink pointing outside of the workbench storage")
+	}
+	if len(fake.uploads) != 2 || fake.uploads[1] != testPipelineYAML {
+		t.Errorf("expected pipeline YAML to be uploaded twice, got %d uploads", len(fake.uploads))
+	}
+}
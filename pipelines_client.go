@@ -0,0 +1,290 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const pipelinesAPIPath = "/apis/v2beta1"
+
+const pipelinesPageSize = 100
+
+// pipelinesClient talks to the Kubeflow Pipelines v2 REST API of a project's pipeline server
+type pipelinesClient struct {
+	baseURL   string
+	namespace string
+	token     string
+}
+
+type pipeline struct {
+	PipelineID  string `json:"pipeline_id,omitempty"`
+	DisplayName string `json:"display_name"`
+	Description string `json:"description,omitempty"`
+	CreatedAt   string `json:"created_at,omitempty"`
+}
+
+type pipelineVersion struct {
+	PipelineID        string `json:"pipeline_id"`
+	PipelineVersionID string `json:"pipeline_version_id,omitempty"`
+	DisplayName       string `json:"display_name"`
+	Description       string `json:"description,omitempty"`
+	CreatedAt         string `json:"created_at,omitempty"`
+}
+
+type pipelineVersionReference struct {
+	PipelineID        string `json:"pipeline_id"`
+	PipelineVersionID string `json:"pipeline_version_id"`
+}
+
+type pipelineRuntimeConfig struct {
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+}
+
+// pipelineStatus is the google.rpc.Status the API uses to report errors of runs and tasks
+type pipelineStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type pipelineTaskDetail struct {
+	TaskID       string          `json:"task_id"`
+	DisplayName  string          `json:"display_name"`
+	State        string          `json:"state"`
+	StartTime    string          `json:"start_time,omitempty"`
+	EndTime      string          `json:"end_time,omitempty"`
+	ParentTaskID string          `json:"parent_task_id,omitempty"`
+	Error        *pipelineStatus `json:"error,omitempty"`
+}
+
+type pipelineRunDetails struct {
+	TaskDetails []pipelineTaskDetail `json:"task_details"`
+}
+
+type pipelineRun struct {
+	RunID                    string                    `json:"run_id,omitempty"`
+	DisplayName              string                    `json:"display_name"`
+	Description              string                    `json:"description,omitempty"`
+	PipelineVersionReference *pipelineVersionReference `json:"pipeline_version_reference,omitempty"`
+	RuntimeConfig            *pipelineRuntimeConfig    `json:"runtime_config,omitempty"`
+	State                    string                    `json:"state,omitempty"`
+	CreatedAt                string                    `json:"created_at,omitempty"`
+	FinishedAt               string                    `json:"finished_at,omitempty"`
+	Error                    *pipelineStatus           `json:"error,omitempty"`
+	RunDetails               *pipelineRunDetails       `json:"run_details,omitempty"`
+}
+
+// newPipelinesClient creates a client for the pipeline server of namespace. When apiURL is empty
+// the URL is read from the status of the project's DataSciencePipelinesApplication. The kubeconfig
+// token is only sent to that URL, a caller supplied URL is called without a token.
+func newPipelinesClient(ctx context.Context, namespace, apiURL string) (*pipelinesClient, error) {
+	token := ""
+	if apiURL == "" {
+		dyn, err := getDynamicClient()
+		if err != nil {
+			return nil, err
+		}
+		server, err := getPipelineServer(ctx, dyn, namespace)
+		if err != nil {
+			return nil, err
+		}
+		if server == nil {
+			return nil, fmt.Errorf("project %s has no pipeline server", namespace)
+		}
+		apiURL = pipelineServerURL(*server)
+		if apiURL == "" {
+			return nil, fmt.Errorf("pipeline server %s is %s and has no API URL yet", server.GetName(), pipelineServerState(*server))
+		}
+		// pipeline servers without oauth proxy (f.e. port forwarded ones) do not need a token
+		token, _ = getClusterToken()
+	}
+
+	return &pipelinesClient{
+		baseURL:   strings.TrimSuffix(apiURL, "/") + pipelinesAPIPath,
+		namespace: namespace,
+		token:     token,
+	}, nil
+}
+
+func (c *pipelinesClient) listPipelines(ctx context.Context) ([]pipeline, error) {
+	return listPipelinesPages[pipeline](ctx, c, "/pipelines", url.Values{"namespace": {c.namespace}}, "pipelines")
+}
+
+// listPipelinesPages follows next_page_token until every item of a list endpoint is fetched,
+// itemsField is the name of the list in the response
+func listPipelinesPages[T any](ctx context.Context, c *pipelinesClient, path string, query url.Values, itemsField string) ([]T, error) {
+	var items []T
+	query.Set("page_size", fmt.Sprintf("%d", pipelinesPageSize))
+	for {
+		var page map[string]json.RawMessage
+		if err := doJSONRequest(ctx, http.MethodGet, c.baseURL+path+"?"+query.Encode(), c.token, nil, &page); err != nil {
+			return nil, err
+		}
+		var pageItems []T
+		if raw, ok := page[itemsField]; ok {
+			if err := json.Unmarshal(raw, &pageItems); err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %v", itemsField, err)
+			}
+		}
+		items = append(items, pageItems...)
+
+		// camel cased responses carry the token as nextPageToken
+		var token string
+		if raw, ok := page["next_page_token"]; ok {
+			_ = json.Unmarshal(raw, &token)
+		} else if raw, ok := page["nextPageToken"]; ok {
+			_ = json.Unmarshal(raw, &token)
+		}
+		if token == "" || token == query.Get("page_token") || len(pageItems) == 0 {
+			return items, nil
+		}
+		query.Set("page_token", token)
+	}
+}
+
+// listPipelineVersions returns the versions of a pipeline, newest first
+func (c *pipelinesClient) listPipelineVersions(ctx context.Context, pipelineID string) ([]pipelineVersion, error) {
+	query := url.Values{"sort_by": {"created_at desc"}}
+	return listPipelinesPages[pipelineVersion](ctx, c, "/pipelines/"+url.PathEscape(pipelineID)+"/versions", query, "pipeline_versions")
+}
+
+// findPipeline returns the pipeline with the given ID or display name, nil when there is none
+func (c *pipelinesClient) findPipeline(ctx context.Context, nameOrID string) (*pipeline, error) {
+	pipelines, err := c.listPipelines(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range pipelines {
+		if p.PipelineID == nameOrID || p.DisplayName == nameOrID {
+			return &p, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *pipelinesClient) createRun(ctx context.Context, run pipelineRun) (pipelineRun, error) {
+	var created pipelineRun
+	err := doJSONRequest(ctx, http.MethodPost, c.baseURL+"/runs", c.token, run, &created)
+	return created, err
+}
+
+// listRuns returns the runs of the namespace, newest first
+func (c *pipelinesClient) listRuns(ctx context.Context) ([]pipelineRun, error) {
+	query := url.Values{"sort_by": {"created_at desc"}, "namespace": {c.namespace}}
+	return listPipelinesPages[pipelineRun](ctx, c, "/runs", query, "runs")
+}
+
+func (c *pipelinesClient) getRun(ctx context.Context, runID string) (pipelineRun, error) {
+	var run pipelineRun
+	err := doJSONRequest(ctx, http.MethodGet, c.baseURL+"/runs/"+url.PathEscape(runID), c.token, nil, &run)
+	return run, err
+}
+
+func (c *pipelinesClient) terminateRun(ctx context.Context, runID string) error {
+	return doJSONRequest(ctx, http.MethodPost, c.baseURL+"/runs/"+url.PathEscape(runID)+":terminate", c.token, nil, nil)
+}
+
+func (c *pipelinesClient) retryRun(ctx context.Context, runID string) error {
+	return doJSONRequest(ctx, http.MethodPost, c.baseURL+"/runs/"+url.PathEscape(runID)+":retry", c.token, nil, nil)
+}
+
+// uploadPipeline creates a new pipeline from a compiled pipeline definition
+func (c *pipelinesClient) uploadPipeline(ctx context.Context, name, description string, content []byte) (pipeline, error) {
+	var created pipeline
+	query := url.Values{"name": {name}, "display_name": {name}, "description": {description}, "namespace": {c.namespace}}
+	err := c.upload(ctx, "/pipelines/upload?"+query.Encode(), content, &created)
+	return created, err
+}
+
+// uploadPipelineVersion adds a compiled pipeline definition as a new version of an existing pipeline
+func (c *pipelinesClient) uploadPipelineVersion(ctx context.Context, pipelineID, name, description string, content []byte) (pipelineVersion, error) {
+	var created pipelineVersion
+	query := url.Values{"pipelineid": {pipelineID}, "name": {name}, "display_name": {name}, "description": {description}}
+	err := c.upload(ctx, "/pipelines/upload_version?"+query.Encode(), content, &created)
+	return created, err
+}
+
+// upload sends content as the multipart uploadfile form field the upload endpoints expect
+func (c *pipelinesClient) upload(ctx context.Context, path string, content []byte, out interface{}) error {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("uploadfile", "pipeline.yaml")
+	if err != nil {
+		return fmt.Errorf("failed to build upload request: %v", err)
+	}
+	if _, err := part.Write(content); err != nil {
+		return fmt.Errorf("failed to build upload request: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to build upload request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, &body)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %v", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	return doRequest(req, out)
+}
+
+type cronSchedule struct {
+	Cron string `json:"cron"`
+}
+
+// periodicSchedule uses a string for the interval as the API encodes int64 values as JSON strings
+type periodicSchedule struct {
+	IntervalSecond int64 `json:"interval_second,string"`
+}
+
+type recurringRunTrigger struct {
+	CronSchedule     *cronSchedule     `json:"cron_schedule,omitempty"`
+	PeriodicSchedule *periodicSchedule `json:"periodic_schedule,omitempty"`
+}
+
+type recurringRun struct {
+	RecurringRunID           string                    `json:"recurring_run_id,omitempty"`
+	DisplayName              string                    `json:"display_name"`
+	Description              string                    `json:"description,omitempty"`
+	PipelineVersionReference *pipelineVersionReference `json:"pipeline_version_reference,omitempty"`
+	RuntimeConfig            *pipelineRuntimeConfig    `json:"runtime_config,omitempty"`
+	Trigger                  recurringRunTrigger       `json:"trigger"`
+	MaxConcurrency           int64                     `json:"max_concurrency,omitempty,string"`
+	// Mode is ENABLE or DISABLE when creating, Status reports ENABLED or DISABLED
+	Mode      string          `json:"mode,omitempty"`
+	Status    string          `json:"status,omitempty"`
+	Namespace string          `json:"namespace,omitempty"`
+	CreatedAt string          `json:"created_at,omitempty"`
+	Error     *pipelineStatus `json:"error,omitempty"`
+}
+
+func (c *pipelinesClient) createRecurringRun(ctx context.Context, run recurringRun) (recurringRun, error) {
+	var created recurringRun
+	err := doJSONRequest(ctx, http.MethodPost, c.baseURL+"/recurringruns", c.token, run, &created)
+	return created, err
+}
+
+func (c *pipelinesClient) listRecurringRuns(ctx context.Context) ([]recurringRun, error) {
+	// unlike the other list responses this one is camel cased
+	return listPipelinesPages[recurringRun](ctx, c, "/recurringruns", url.Values{"namespace": {c.namespace}}, "recurringRuns")
+}
+
+func (c *pipelinesClient) enableRecurringRun(ctx context.Context, recurringRunID string) error {
+	return doJSONRequest(ctx, http.MethodPost, c.baseURL+"/recurringruns/"+url.PathEscape(recurringRunID)+":enable", c.token, nil, nil)
+}
+
+func (c *pipelinesClient) disableRecurringRun(ctx context.Context, recurringRunID string) error {
+	return doJSONRequest(ctx, http.MethodPost, c.baseURL+"/recurringruns/"+url.PathEscape(recurringRunID)+":disable", c.token, nil, nil)
+}
+
+func (c *pipelinesClient) deleteRecurringRun(ctx context.Context, recurringRunID string) error {
+	return doJSONRequest(ctx, http.MethodDelete, c.baseURL+"/recurringruns/"+url.PathEscape(recurringRunID), c.token, nil, nil)
+}
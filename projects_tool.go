@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+)
+
+// Lists the namespaces labelled as data science projects
+func ListProjects(ctx context.Context, req *mcp.CallToolRequest, input ListProjectsInput) (*mcp.CallToolResult, ListProjectsOutput, error) {
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, ListProjectsOutput{}, err
+	}
+
+	projects, err := dyn.Resource(projectsGVR).List(ctx, metav1.ListOptions{
+		LabelSelector: "opendatahub.io/dashboard=true",
+	})
+	if err != nil {
+		return nil, ListProjectsOutput{}, fmt.Errorf("failed to list projects: %v", err)
+	}
+
+	msg := ""
+	for _, project := range projects.Items {
+		annotations := project.GetAnnotations()
+		msg += fmt.Sprintf("- %s", project.GetName())
+		if displayName := annotations["openshift.io/display-name"]; displayName != "" {
+			msg += fmt.Sprintf(" (%s)", displayName)
+		}
+		if description := annotations["openshift.io/description"]; description != "" {
+			msg += fmt.Sprintf(": %s", description)
+		}
+		msg += "\n"
+	}
+	return nil, ListProjectsOutput{Projects: msg}, nil
+}
+
+func CreateProject(ctx context.Context, req *mcp.CallToolRequest, input CreateProjectInput) (*mcp.CallToolResult, ProjectOutput, error) {
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, ProjectOutput{}, err
+	}
+
+	displayName := input.DisplayName
+	if displayName == "" {
+		displayName = input.Name
+	}
+
+	projectRequest := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "project.openshift.io/v1",
+			"kind":       "ProjectRequest",
+			"metadata": map[string]interface{}{
+				"name": input.Name,
+			},
+			"displayName": displayName,
+			"description": input.Description,
+		},
+	}
+
+	_, err = dyn.Resource(projectRequestsGVR).Create(ctx, projectRequest, metav1.CreateOptions{})
+	if err != nil {
+		return nil, ProjectOutput{}, fmt.Errorf("failed to create project %s: %v", input.Name, err)
+	}
+
+	// the dashboard only shows namespaces carrying its label
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{
+				"opendatahub.io/dashboard": "true",
+			},
+		},
+	}
+	patchBytes, err := json.Marshal(patch)
+	if err != nil {
+		return nil, ProjectOutput{}, fmt.Errorf("failed to marshal patch: %v", err)
+	}
+
+	// project admins usually may not patch their namespace, the project exists either way
+	msg := fmt.Sprintf("Project %s was succesfully created!", input.Name)
+	_, err = dyn.Resource(namespacesGVR).Patch(ctx, input.Name, k8stypes.MergePatchType, patchBytes, metav1.PatchOptions{})
+	if errors.IsForbidden(err) {
+		msg += " Warning: it could not be labelled opendatahub.io/dashboard=true, so it is not shown in the dashboard until a cluster admin adds the label"
+	} else if err != nil {
+		return nil, ProjectOutput{}, fmt.Errorf("project %s was created but labelling it failed: %v", input.Name, err)
+	}
+
+	return nil, ProjectOutput{Message: msg}, nil
+}
+
+func DeleteProject(ctx context.Context, req *mcp.CallToolRequest, input ProjectInput) (*mcp.CallToolResult, ProjectOutput, error) {
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, ProjectOutput{}, err
+	}
+
+	project, err := dyn.Resource(projectsGVR).Get(ctx, input.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, ProjectOutput{}, fmt.Errorf("failed to get project %s: %v", input.Name, err)
+	}
+	// refuse to delete namespaces which are not data science projects
+	if project.GetLabels()["opendatahub.io/dashboard"] != "true" {
+		return nil, ProjectOutput{}, fmt.Errorf("namespace %s is not a data science project", input.Name)
+	}
+
+	err = dyn.Resource(projectsGVR).Delete(ctx, input.Name, metav1.DeleteOptions{})
+	if err != nil {
+		return nil, ProjectOutput{}, fmt.Errorf("failed to delete project %s: %v", input.Name, err)
+	}
+
+	return nil, ProjectOutput{Message: fmt.Sprintf("Project %s was deleted", input.Name)}, nil
+}
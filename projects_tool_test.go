@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestListProjects(t *testing.T) {
+	orig := getDynamicClient
+	defer func() { getDynamicClient = orig }()
+
+	scheme := runtime.NewScheme()
+	client := dynamicfake.NewSimpleDynamicClient(scheme,
+		newUnstructuredProject("fraud", "Fraud Detection", true),
+		newUnstructuredProject("openshift-monitoring", "", false),
+	)
+	getDynamicClient = func() (dynamic.Interface, error) {
+		return client, nil
+	}
+
+	_, out, err := ListProjects(context.Background(), nil, ListProjectsInput{})
+	if err != nil {
+		t.Fatalf("ListProjects returned error: %v", err)
+	}
+	if !strings.Contains(out.Projects, "- fraud (Fraud Detection)\n") {
+		t.Errorf("expected fraud project in output, got: %q", out.Projects)
+	}
+	if strings.Contains(out.Projects, "openshift-monitoring") {
+		t.Errorf("did not expect openshift-monitoring in output, got: %q", out.Projects)
+	}
+}
+
+func TestCreateProject(t *testing.T) {
+	orig := getDynamicClient
+	defer func() { getDynamicClient = orig }()
+
+	// the namespace would be created by the project request on a real cluster
+	namespace := &unstructured.Unstructured{}
+	namespace.SetGroupVersionKind(namespacesGVR.GroupVersion().WithKind("Namespace"))
+	namespace.SetName("fraud")
+
+	scheme := runtime.NewScheme()
+	client := dynamicfake.NewSimpleDynamicClient(scheme, namespace)
+	getDynamicClient = func() (dynamic.Interface, error) {
+		return client, nil
+	}
+
+	_, out, err := CreateProject(context.Background(), nil, CreateProjectInput{Name: "fraud", DisplayName: "Fraud Detection"})
+	if err != nil {
+		t.Fatalf("CreateProject returned error: %v", err)
+	}
+	if out.Message != "Project fraud was succesfully created!" {
+		t.Errorf("unexpected message: %q", out.Message)
+	}
+
+	projectRequest, err := client.Resource(projectRequestsGVR).Get(context.Background(), "fraud", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected project request to be created: %v", err)
+	}
+	if displayName, _, _ := unstructured.NestedString(projectRequest.Object, "displayName"); displayName != "Fraud Detection" {
+		t.Errorf("expected display name Fraud Detection, got: %q", displayName)
+	}
+
+	labelled, err := client.Resource(namespacesGVR).Get(context.Background(), "fraud", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get namespace: %v", err)
+	}
+	if labelled.GetLabels()["opendatahub.io/dashboard"] != "true" {
+		t.Errorf("expected namespace to be labelled for the dashboard, got: %v", labelled.GetLabels())
+	}
+}
+
+func TestCreateProjectLabelForbidden(t *testing.T) {
+	orig := getDynamicClient
+	defer func() { getDynamicClient = orig }()
+
+	namespace := &unstructured.Unstructured{}
+	namespace.SetGroupVersionKind(namespacesGVR.GroupVersion().WithKind("Namespace"))
+	namespace.SetName("fraud")
+
+	scheme := runtime.NewScheme()
+	client := dynamicfake.NewSimpleDynamicClient(scheme, namespace)
+	client.PrependReactor("patch", "namespaces", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.NewForbidden(namespacesGVR.GroupResource(), "fraud", fmt.Errorf("project admins cannot patch namespaces"))
+	})
+	getDynamicClient = func() (dynamic.Interface, error) {
+		return client, nil
+	}
+
+	_, out, err := CreateProject(context.Background(), nil, CreateProjectInput{Name: "fraud"})
+	if err != nil {
+		t.Fatalf("CreateProject returned error for a forbidden label patch: %v", err)
+	}
+	if !strings.HasPrefix(out.Message, "Project fraud was succesfully created! Warning:") {
+		t.Errorf("expected success message with a warning, got: %q", out.Message)
+	}
+}
+
+func TestDeleteProject(t *testing.T) {
+	orig := getDynamicClient
+	defer func() { getDynamicClient = orig }()
+
+	scheme := runtime.NewScheme()
+	client := dynamicfake.NewSimpleDynamicClient(scheme,
+		newUnstructuredProject("fraud", "Fraud Detection", true),
+		newUnstructuredProject("openshift-monitoring", "", false),
+	)
+	getDynamicClient = func() (dynamic.Interface, error) {
+		return client, nil
+	}
+
+	if _, _, err := DeleteProject(context.Background(), nil, ProjectInput{Name: "openshift-monitoring"}); err == nil {
+		t.Errorf("expected error when deleting a namespace which is not a data science project")
+	}
+
+	_, out, err := DeleteProject(context.Background(), nil, ProjectInput{Name: "fraud"})
+	if err != nil {
+		t.Fatalf("DeleteProject returned error: %v", err)
+	}
+	if out.Message != "Project fraud was deleted" {
+		t.Errorf("unexpected message: %q", out.Message)
+	}
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+// image used by the CodeFlare SDK when none is given
+const defaultRayImage = "quay.io/modh/ray:2.35.0-py311-cu121"
+
+// Lists the ray clusters of a project with their state, worker readiness and dashboard URL
+func ListRayClusters(ctx context.Context, req *mcp.CallToolRequest, input ListWorkbenchesInput) (*mcp.CallToolResult, ListRayClustersOutput, error) {
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, ListRayClustersOutput{}, err
+	}
+
+	clusters, err := dyn.Resource(rayClustersGVR).Namespace(input.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, ListRayClustersOutput{}, fmt.Errorf("failed to list ray clusters: %v", err)
+	}
+	if len(clusters.Items) == 0 {
+		return nil, ListRayClustersOutput{RayClusters: fmt.Sprintf("No ray clusters found in project %s", input.Namespace)}, nil
+	}
+
+	msg := ""
+	for _, cluster := range clusters.Items {
+		state, _, _ := unstructured.NestedString(cluster.Object, "status", "state")
+		readyWorkers, _, _ := unstructured.NestedInt64(cluster.Object, "status", "readyWorkerReplicas")
+		desiredWorkers, _, _ := unstructured.NestedInt64(cluster.Object, "status", "desiredWorkerReplicas")
+		msg += fmt.Sprintf("- %s: %s, %d/%d workers ready", cluster.GetName(), valueOrUnknown(state), readyWorkers, desiredWorkers)
+		if queue := cluster.GetLabels()["kueue.x-k8s.io/queue-name"]; queue != "" {
+			msg += fmt.Sprintf(", queue %s", queue)
+		}
+		msg += fmt.Sprintf(", dashboard %s\n", rayDashboardURL(ctx, dyn, cluster))
+	}
+	return nil, ListRayClustersOutput{RayClusters: msg}, nil
+}
+
+// Creates a ray cluster with a head and one worker group, optionally queued through Kueue
+func CreateRayCluster(ctx context.Context, req *mcp.CallToolRequest, input CreateRayClusterInput) (*mcp.CallToolResult, RayClusterOutput, error) {
+	if input.Workers < 0 || input.WorkerGPUs < 0 {
+		return nil, RayClusterOutput{}, fmt.Errorf("workers and worker GPUs can not be negative")
+	}
+
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, RayClusterOutput{}, err
+	}
+
+	if input.LocalQueue != "" {
+		if _, err := dyn.Resource(localQueuesGVR).Namespace(input.Namespace).Get(ctx, input.LocalQueue, metav1.GetOptions{}); err != nil {
+			return nil, RayClusterOutput{}, fmt.Errorf("failed to get local queue %s: %v", input.LocalQueue, err)
+		}
+	}
+
+	_, err = dyn.Resource(rayClustersGVR).Namespace(input.Namespace).Create(ctx, newRayCluster(input), metav1.CreateOptions{})
+	if err != nil {
+		return nil, RayClusterOutput{}, fmt.Errorf("failed to create ray cluster: %v", err)
+	}
+
+	return nil, RayClusterOutput{Message: fmt.Sprintf("Ray cluster %s with %d workers was succesfully created!", input.Name, input.Workers)}, nil
+}
+
+// Scales a worker group of a ray cluster to the given number of replicas
+func ScaleRayCluster(ctx context.Context, req *mcp.CallToolRequest, input ScaleRayClusterInput) (*mcp.CallToolResult, RayClusterOutput, error) {
+	if input.Workers < 0 {
+		return nil, RayClusterOutput{}, fmt.Errorf("workers can not be negative")
+	}
+
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, RayClusterOutput{}, err
+	}
+
+	cluster, err := dyn.Resource(rayClustersGVR).Namespace(input.Namespace).Get(ctx, input.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, RayClusterOutput{}, fmt.Errorf("failed to get ray cluster %s: %v", input.Name, err)
+	}
+
+	// worker groups are a list, so the whole list is updated instead of merge patched
+	groups, _, _ := unstructured.NestedSlice(cluster.Object, "spec", "workerGroupSpecs")
+	if input.WorkerGroup == "" && len(groups) > 1 {
+		return nil, RayClusterOutput{}, fmt.Errorf("ray cluster %s has %d worker groups, choose one to scale", input.Name, len(groups))
+	}
+	scaled := ""
+	for _, g := range groups {
+		group, ok := g.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		groupName, _ := group["groupName"].(string)
+		if input.WorkerGroup != "" && groupName != input.WorkerGroup {
+			continue
+		}
+		replicas := int64(input.Workers)
+		group["replicas"] = replicas
+		group["minReplicas"] = replicas
+		group["maxReplicas"] = replicas
+		scaled = groupName
+		break
+	}
+	if scaled == "" {
+		return nil, RayClusterOutput{}, fmt.Errorf("worker group %s not found in ray cluster %s", input.WorkerGroup, input.Name)
+	}
+	if err := unstructured.SetNestedSlice(cluster.Object, groups, "spec", "workerGroupSpecs"); err != nil {
+		return nil, RayClusterOutput{}, fmt.Errorf("failed to set worker groups: %v", err)
+	}
+
+	_, err = dyn.Resource(rayClustersGVR).Namespace(input.Namespace).Update(ctx, cluster, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, RayClusterOutput{}, fmt.Errorf("failed to scale ray cluster %s: %v", input.Name, err)
+	}
+
+	return nil, RayClusterOutput{Message: fmt.Sprintf("Worker group %s of ray cluster %s was scaled to %d workers", scaled, input.Name, input.Workers)}, nil
+}
+
+// Deletes a ray cluster together with its head and worker pods
+func DeleteRayCluster(ctx context.Context, req *mcp.CallToolRequest, input RayClusterInput) (*mcp.CallToolResult, RayClusterOutput, error) {
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, RayClusterOutput{}, err
+	}
+
+	err = dyn.Resource(rayClustersGVR).Namespace(input.Namespace).Delete(ctx, input.Name, metav1.DeleteOptions{})
+	if err != nil {
+		return nil, RayClusterOutput{}, fmt.Errorf("failed to delete ray cluster %s: %v", input.Name, err)
+	}
+
+	return nil, RayClusterOutput{Message: fmt.Sprintf("Ray cluster %s was deleted", input.Name)}, nil
+}
+
+func newRayCluster(input CreateRayClusterInput) *unstructured.Unstructured {
+	image := input.Image
+	if image == "" {
+		image = defaultRayImage
+	}
+
+	headResources := containerResources(input.HeadCPU, "2", input.HeadMemory, "8Gi", 0)
+	workerResources := containerResources(input.WorkerCPU, "1", input.WorkerMemory, "4Gi", input.WorkerGPUs)
+	workers := int64(input.Workers)
+
+	labels := map[string]interface{}{
+		"opendatahub.io/dashboard": "true",
+	}
+	if input.LocalQueue != "" {
+		labels["kueue.x-k8s.io/queue-name"] = input.LocalQueue
+	}
+
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "ray.io/v1",
+			"kind":       "RayCluster",
+			"metadata": map[string]interface{}{
+				"name":      input.Name,
+				"namespace": input.Namespace,
+				"labels":    labels,
+			},
+			"spec": map[string]interface{}{
+				"enableInTreeAutoscaling": false,
+				"headGroupSpec": map[string]interface{}{
+					"serviceType": "ClusterIP",
+					"rayStartParams": map[string]interface{}{
+						"dashboard-host": "0.0.0.0",
+						"block":          "true",
+						"num-gpus":       "0",
+					},
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"containers": []interface{}{
+								map[string]interface{}{
+									"name":  "ray-head",
+									"image": image,
+									"ports": []interface{}{
+										map[string]interface{}{"name": "gcs", "containerPort": int64(6379)},
+										map[string]interface{}{"name": "dashboard", "containerPort": int64(8265)},
+										map[string]interface{}{"name": "client", "containerPort": int64(10001)},
+									},
+									"resources": headResources,
+								},
+							},
+						},
+					},
+				},
+				"workerGroupSpecs": []interface{}{
+					map[string]interface{}{
+						"groupName":   "small-group-" + input.Name,
+						"replicas":    workers,
+						"minReplicas": workers,
+						"maxReplicas": workers,
+						"rayStartParams": map[string]interface{}{
+							"block":    "true",
+							"num-gpus": fmt.Sprintf("%d", input.WorkerGPUs),
+						},
+						"template": map[string]interface{}{
+							"spec": map[string]interface{}{
+								"containers": []interface{}{
+									map[string]interface{}{
+										"name":      "machine-learning",
+										"image":     image,
+										"resources": workerResources,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// containerResources requests and limits the same CPU, memory and GPUs, using the defaults for empty values
+func containerResources(cpu, defaultCPU, memory, defaultMemory string, gpus int) map[string]interface{} {
+	if cpu == "" {
+		cpu = defaultCPU
+	}
+	if memory == "" {
+		memory = defaultMemory
+	}
+	requests := map[string]interface{}{"cpu": cpu, "memory": memory}
+	limits := map[string]interface{}{"cpu": cpu, "memory": memory}
+	if gpus > 0 {
+		requests["nvidia.com/gpu"] = fmt.Sprintf("%d", gpus)
+		limits["nvidia.com/gpu"] = fmt.Sprintf("%d", gpus)
+	}
+	return map[string]interface{}{
+		"requests": requests,
+		"limits":   limits,
+	}
+}
+
+// rayDashboardURL returns the URL of the route the CodeFlare operator creates for the ray dashboard,
+// or the cluster internal URL of the head service when there is no route or it cannot be read
+func rayDashboardURL(ctx context.Context, dyn dynamic.Interface, cluster unstructured.Unstructured) string {
+	route, err := dyn.Resource(routesGVR).Namespace(cluster.GetNamespace()).Get(ctx, "ray-dashboard-"+cluster.GetName(), metav1.GetOptions{})
+	if err == nil {
+		if host, _, _ := unstructured.NestedString(route.Object, "spec", "host"); host != "" {
+			return "https://" + host
+		}
+	}
+	return fmt.Sprintf("http://%s-head-svc.%s.svc:8265", cluster.GetName(), cluster.GetNamespace())
+}
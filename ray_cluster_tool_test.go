@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newUnstructuredRoute(name, namespace, host string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"host": host},
+	}}
+	u.SetGroupVersionKind(routesGVR.GroupVersion().WithKind("Route"))
+	u.SetName(name)
+	u.SetNamespace(namespace)
+	return u
+}
+
+func TestRayClusters(t *testing.T) {
+	orig := getDynamicClient
+	defer func() { getDynamicClient = orig }()
+
+	scheme := runtime.NewScheme()
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme,
+		map[schema.GroupVersionResource]string{rayClustersGVR: "RayClusterList"},
+		newUnstructuredLocalQueue("local-queue", "ns1", "team-a", 0, 0),
+		newUnstructuredRoute("ray-dashboard-training", "ns1", "ray-dashboard-training-ns1.apps.example.com"),
+	)
+	getDynamicClient = func() (dynamic.Interface, error) {
+		return client, nil
+	}
+	ctx := context.Background()
+
+	if _, _, err := CreateRayCluster(ctx, nil, CreateRayClusterInput{Namespace: "ns1", Name: "training", Workers: 2, LocalQueue: "missing"}); err == nil {
+		t.Errorf("expected error for missing local queue")
+	}
+
+	_, out, err := CreateRayCluster(ctx, nil, CreateRayClusterInput{Namespace: "ns1", Name: "training", Workers: 2, WorkerGPUs: 1, LocalQueue: "local-queue"})
+	if err != nil {
+		t.Fatalf("CreateRayCluster returned error: %v", err)
+	}
+	if out.Message != "Ray cluster training with 2 workers was succesfully created!" {
+		t.Errorf("unexpected message: %q", out.Message)
+	}
+	if _, _, err := CreateRayCluster(ctx, nil, CreateRayClusterInput{Namespace: "ns1", Name: "scratch", Workers: 1}); err != nil {
+		t.Fatalf("CreateRayCluster returned error: %v", err)
+	}
+
+	created, err := client.Resource(rayClustersGVR).Namespace("ns1").Get(ctx, "training", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected ray cluster to be created: %v", err)
+	}
+	if created.GetLabels()["kueue.x-k8s.io/queue-name"] != "local-queue" {
+		t.Errorf("expected kueue queue label, got: %v", created.GetLabels())
+	}
+	groups, _, _ := unstructured.NestedSlice(created.Object, "spec", "workerGroupSpecs")
+	worker := groups[0].(map[string]interface{})
+	containers, _, _ := unstructured.NestedSlice(worker, "template", "spec", "containers")
+	limits, _, _ := unstructured.NestedStringMap(containers[0].(map[string]interface{}), "resources", "limits")
+	if worker["replicas"] != int64(2) || limits["nvidia.com/gpu"] != "1" || limits["memory"] != "4Gi" {
+		t.Errorf("unexpected worker group: replicas %v, limits %v", worker["replicas"], limits)
+	}
+
+	// pretend the ray operator reconciled the cluster
+	_ = unstructured.SetNestedField(created.Object, "ready", "status", "state")
+	_ = unstructured.SetNestedField(created.Object, int64(1), "status", "readyWorkerReplicas")
+	_ = unstructured.SetNestedField(created.Object, int64(2), "status", "desiredWorkerReplicas")
+	if _, err := client.Resource(rayClustersGVR).Namespace("ns1").Update(ctx, created, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("failed to update ray cluster status: %v", err)
+	}
+
+	_, list, err := ListRayClusters(ctx, nil, ListWorkbenchesInput{Namespace: "ns1"})
+	if err != nil {
+		t.Fatalf("ListRayClusters returned error: %v", err)
+	}
+	for _, expected := range []string{
+		"- training: ready, 1/2 workers ready, queue local-queue, dashboard https://ray-dashboard-training-ns1.apps.example.com\n",
+		"- scratch: unknown, 0/0 workers ready, dashboard http://scratch-head-svc.ns1.svc:8265\n",
+	} {
+		if !strings.Contains(list.RayClusters, expected) {
+			t.Errorf("expected %q in output, got:\n%s", expected, list.RayClusters)
+		}
+	}
+
+	// users who cannot read routes still get the listing with the internal dashboard URL
+	client.PrependReactor("get", "routes", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.NewForbidden(routesGVR.GroupResource(), "ray-dashboard-training", fmt.Errorf("routes are forbidden"))
+	})
+	_, list, err = ListRayClusters(ctx, nil, ListWorkbenchesInput{Namespace: "ns1"})
+	if err != nil {
+		t.Fatalf("ListRayClusters returned error when routes are forbidden: %v", err)
+	}
+	if !strings.Contains(list.RayClusters, "dashboard http://training-head-svc.ns1.svc:8265\n") {
+		t.Errorf("expected internal dashboard URL when routes are forbidden, got:\n%s", list.RayClusters)
+	}
+
+	if _, _, err := ScaleRayCluster(ctx, nil, ScaleRayClusterInput{Namespace: "ns1", Name: "training", Workers: 4, WorkerGroup: "missing"}); err == nil {
+		t.Errorf("expected error for missing worker group")
+	}
+	_, out, err = ScaleRayCluster(ctx, nil, ScaleRayClusterInput{Namespace: "ns1", Name: "training", Workers: 4})
+	if err != nil {
+		t.Fatalf("ScaleRayCluster returned error: %v", err)
+	}
+	if out.Message != "Worker group small-group-training of ray cluster training was scaled to 4 workers" {
+		t.Errorf("unexpected message: %q", out.Message)
+	}
+	scaled, _ := client.Resource(rayClustersGVR).Namespace("ns1").Get(ctx, "training", metav1.GetOptions{})
+	groups, _, _ = unstructured.NestedSlice(scaled.Object, "spec", "workerGroupSpecs")
+	if groups[0].(map[string]interface{})["maxReplicas"] != int64(4) {
+		t.Errorf("expected worker group to be scaled, got: %v", groups[0])
+	}
+
+	if _, _, err := DeleteRayCluster(ctx, nil, RayClusterInput{Namespace: "ns1", Name: "training"}); err != nil {
+		t.Fatalf("DeleteRayCluster returned error: %v", err)
+	}
+	if _, err := client.Resource(rayClustersGVR).Namespace("ns1").Get(ctx, "training", metav1.GetOptions{}); err == nil {
+		t.Errorf("expected ray cluster to be deleted")
+	}
+}
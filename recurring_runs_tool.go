@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Creates a recurring run of a pipeline version triggered by a cron expression or an interval
+func CreateRecurringRun(ctx context.Context, req *mcp.CallToolRequest, input CreateRecurringRunInput) (*mcp.CallToolResult, PipelineRunOutput, error) {
+	trigger, err := newRecurringRunTrigger(input.Cron, input.IntervalSeconds)
+	if err != nil {
+		return nil, PipelineRunOutput{}, err
+	}
+	maxConcurrency := input.MaxConcurrency
+	if maxConcurrency == 0 {
+		maxConcurrency = 1
+	}
+
+	client, err := newPipelinesClient(ctx, input.Namespace, input.APIURL)
+	if err != nil {
+		return nil, PipelineRunOutput{}, err
+	}
+
+	p, versionReference, err := resolvePipelineVersion(ctx, client, input.Namespace, input.Pipeline, input.VersionID)
+	if err != nil {
+		return nil, PipelineRunOutput{}, err
+	}
+
+	created, err := client.createRecurringRun(ctx, recurringRun{
+		DisplayName:              input.Name,
+		PipelineVersionReference: versionReference,
+		RuntimeConfig:            &pipelineRuntimeConfig{Parameters: input.Parameters},
+		Trigger:                  trigger,
+		MaxConcurrency:           maxConcurrency,
+		Mode:                     "ENABLE",
+		Namespace:                input.Namespace,
+	})
+	if err != nil {
+		return nil, PipelineRunOutput{}, fmt.Errorf("failed to create recurring run: %v", err)
+	}
+
+	return nil, PipelineRunOutput{Message: fmt.Sprintf("Recurring run %s of pipeline %s was succesfully created with ID %s, %s", input.Name, p.DisplayName, created.RecurringRunID, recurringRunSchedule(trigger))}, nil
+}
+
+// Lists the recurring runs of the project's pipeline server with their schedule and status
+func ListRecurringRuns(ctx context.Context, req *mcp.CallToolRequest, input PipelineServerInput) (*mcp.CallToolResult, ListRecurringRunsOutput, error) {
+	client, err := newPipelinesClient(ctx, input.Namespace, input.APIURL)
+	if err != nil {
+		return nil, ListRecurringRunsOutput{}, err
+	}
+
+	runs, err := client.listRecurringRuns(ctx)
+	if err != nil {
+		return nil, ListRecurringRunsOutput{}, fmt.Errorf("failed to list recurring runs: %v", err)
+	}
+	if len(runs) == 0 {
+		return nil, ListRecurringRunsOutput{RecurringRuns: fmt.Sprintf("No recurring runs found in project %s", input.Namespace)}, nil
+	}
+
+	msg := ""
+	for _, run := range runs {
+		msg += fmt.Sprintf("- %s (ID %s): %s, %s\n", run.DisplayName, run.RecurringRunID, run.Status, recurringRunSchedule(run.Trigger))
+	}
+	return nil, ListRecurringRunsOutput{RecurringRuns: msg}, nil
+}
+
+// Pauses a recurring run so it stops starting new runs
+func PauseRecurringRun(ctx context.Context, req *mcp.CallToolRequest, input RecurringRunInput) (*mcp.CallToolResult, PipelineRunOutput, error) {
+	client, err := newPipelinesClient(ctx, input.Namespace, input.APIURL)
+	if err != nil {
+		return nil, PipelineRunOutput{}, err
+	}
+
+	if err := client.disableRecurringRun(ctx, input.RecurringRunID); err != nil {
+		return nil, PipelineRunOutput{}, fmt.Errorf("failed to pause recurring run: %v", err)
+	}
+	return nil, PipelineRunOutput{Message: fmt.Sprintf("Recurring run %s was paused", input.RecurringRunID)}, nil
+}
+
+// Resumes a paused recurring run
+func ResumeRecurringRun(ctx context.Context, req *mcp.CallToolRequest, input RecurringRunInput) (*mcp.CallToolResult, PipelineRunOutput, error) {
+	client, err := newPipelinesClient(ctx, input.Namespace, input.APIURL)
+	if err != nil {
+		return nil, PipelineRunOutput{}, err
+	}
+
+	if err := client.enableRecurringRun(ctx, input.RecurringRunID); err != nil {
+		return nil, PipelineRunOutput{}, fmt.Errorf("failed to resume recurring run: %v", err)
+	}
+	return nil, PipelineRunOutput{Message: fmt.Sprintf("Recurring run %s was resumed", input.RecurringRunID)}, nil
+}
+
+// Deletes a recurring run, runs it already started are kept
+func DeleteRecurringRun(ctx context.Context, req *mcp.CallToolRequest, input RecurringRunInput) (*mcp.CallToolResult, PipelineRunOutput, error) {
+	client, err := newPipelinesClient(ctx, input.Namespace, input.APIURL)
+	if err != nil {
+		return nil, PipelineRunOutput{}, err
+	}
+
+	if err := client.deleteRecurringRun(ctx, input.RecurringRunID); err != nil {
+		return nil, PipelineRunOutput{}, fmt.Errorf("failed to delete recurring run: %v", err)
+	}
+	return nil, PipelineRunOutput{Message: fmt.Sprintf("Recurring run %s was deleted", input.RecurringRunID)}, nil
+}
+
+// newRecurringRunTrigger builds the trigger from exactly one of cron and interval. The scheduler
+// expects cron expressions with seconds, so standard five field expressions get a leading 0.
+func newRecurringRunTrigger(cron string, intervalSeconds int64) (recurringRunTrigger, error) {
+	cron = strings.TrimSpace(cron)
+	if (cron == "") == (intervalSeconds == 0) {
+		return recurringRunTrigger{}, fmt.Errorf("exactly one of cron and intervalSeconds is required")
+	}
+	if intervalSeconds < 0 {
+		return recurringRunTrigger{}, fmt.Errorf("intervalSeconds must be positive")
+	}
+	if intervalSeconds > 0 {
+		return recurringRunTrigger{PeriodicSchedule: &periodicSchedule{IntervalSecond: intervalSeconds}}, nil
+	}
+
+	fields := strings.Fields(cron)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return recurringRunTrigger{}, fmt.Errorf("cron expression %q must have 5 or 6 fields", cron)
+	}
+	return recurringRunTrigger{CronSchedule: &cronSchedule{Cron: strings.Join(fields, " ")}}, nil
+}
+
+func recurringRunSchedule(trigger recurringRunTrigger) string {
+	switch {
+	case trigger.CronSchedule != nil:
+		return fmt.Sprintf("cron %s", trigger.CronSchedule.Cron)
+	case trigger.PeriodicSchedule != nil:
+		return fmt.Sprintf("every %d seconds", trigger.PeriodicSchedule.IntervalSecond)
+	}
+	return "no schedule"
+}
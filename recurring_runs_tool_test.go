@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNewRecurringRunTrigger(t *testing.T) {
+	trigger, err := newRecurringRunTrigger("0 2 * * *", 0)
+	if err != nil || trigger.CronSchedule == nil || trigger.CronSchedule.Cron != "0 0 2 * * *" {
+		t.Errorf("expected five field cron to get seconds, got %+v, %v", trigger.CronSchedule, err)
+	}
+	trigger, err = newRecurringRunTrigger("30 0 2 * * 1", 0)
+	if err != nil || trigger.CronSchedule.Cron != "30 0 2 * * 1" {
+		t.Errorf("expected six field cron to be kept, got %+v, %v", trigger.CronSchedule, err)
+	}
+	trigger, err = newRecurringRunTrigger("", 3600)
+	if err != nil || trigger.PeriodicSchedule == nil || trigger.PeriodicSchedule.IntervalSecond != 3600 {
+		t.Errorf("expected periodic schedule, got %+v, %v", trigger.PeriodicSchedule, err)
+	}
+
+	for _, invalid := range []struct {
+		cron     string
+		interval int64
+	}{{"", 0}, {"0 2 * * *", 60}, {"0 2 *", 0}, {"", -5}} {
+		if _, err := newRecurringRunTrigger(invalid.cron, invalid.interval); err == nil {
+			t.Errorf("expected error for cron %q and interval %d", invalid.cron, invalid.interval)
+		}
+	}
+}
+
+func TestRecurringRuns(t *testing.T) {
+	fake := &fakePipelinesServer{
+		pipelines: []pipeline{{PipelineID: "p1", DisplayName: "training"}},
+		versions:  []pipelineVersion{{PipelineID: "p1", PipelineVersionID: "v1", DisplayName: "training v1"}},
+	}
+	server := newFakePipelinesServer(t, fake)
+	ctx := context.Background()
+
+	_, out, err := CreateRecurringRun(ctx, nil, CreateRecurringRunInput{
+		Namespace:  "ns1",
+		APIURL:     server.URL,
+		Pipeline:   "training",
+		Name:       "nightly-retraining",
+		Parameters: map[string]interface{}{"epochs": 10},
+		Cron:       "0 2 * * *",
+	})
+	if err != nil {
+		t.Fatalf("CreateRecurringRun returned error: %v", err)
+	}
+	if out.Message != "Recurring run nightly-retraining of pipeline training was succesfully created with ID rr-1, cron 0 0 2 * * *" {
+		t.Errorf("unexpected message: %q", out.Message)
+	}
+	created := fake.recurring[0]
+	if created.PipelineVersionReference.PipelineVersionID != "v1" || created.MaxConcurrency != 1 || created.Mode != "ENABLE" {
+		t.Errorf("unexpected recurring run: %+v", created)
+	}
+
+	if _, _, err := CreateRecurringRun(ctx, nil, CreateRecurringRunInput{Namespace: "ns1", APIURL: server.URL, Pipeline: "training", Name: "hourly", IntervalSeconds: 3600}); err != nil {
+		t.Fatalf("CreateRecurringRun returned error: %v", err)
+	}
+
+	if _, _, err := PauseRecurringRun(ctx, nil, RecurringRunInput{Namespace: "ns1", APIURL: server.URL, RecurringRunID: "rr-1"}); err != nil {
+		t.Fatalf("PauseRecurringRun returned error: %v", err)
+	}
+	_, list, err := ListRecurringRuns(ctx, nil, PipelineServerInput{Namespace: "ns1", APIURL: server.URL})
+	if err != nil {
+		t.Fatalf("ListRecurringRuns returned error: %v", err)
+	}
+	if list.RecurringRuns != "- nightly-retraining (ID rr-1): DISABLED, cron 0 0 2 * * *\n- hourly (ID rr-2): ENABLED, every 3600 seconds\n" {
+		t.Errorf("unexpected recurring runs: %q", list.RecurringRuns)
+	}
+
+	if _, _, err := ResumeRecurringRun(ctx, nil, RecurringRunInput{Namespace: "ns1", APIURL: server.URL, RecurringRunID: "rr-1"}); err != nil {
+		t.Fatalf("ResumeRecurringRun returned error: %v", err)
+	}
+	if fake.recurring[0].Status != "ENABLED" {
+		t.Errorf("expected recurring run to be enabled, got %s", fake.recurring[0].Status)
+	}
+
+	if _, _, err := DeleteRecurringRun(ctx, nil, RecurringRunInput{Namespace: "ns1", APIURL: server.URL, RecurringRunID: "rr-2"}); err != nil {
+		t.Fatalf("DeleteRecurringRun returned error: %v", err)
+	}
+	if _, _, err := DeleteRecurringRun(ctx, nil, RecurringRunInput{Namespace: "ns1", APIURL: server.URL, RecurringRunID: "rr-2"}); err == nil {
+		t.Errorf("expected error when deleting a missing recurring run")
+	}
+	if len(fake.recurring) != 1 {
+		t.Errorf("expected one recurring run to be left, got %d", len(fake.recurring))
+	}
+}
@@ -4,17 +4,45 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
 )
 
 type ImageDef struct {
-	Name     string   `json:"name"`
-	URL      string   `json:"url"`
-	Versions []string `json:"versions"`
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	URL         string        `json:"url"`
+	Versions    []string      `json:"versions"`
+	Tags        []ImageTagDef `json:"tags"`
+}
+
+// ImageTagDef describes the software the dashboard shows for one tag of a notebook image
+type ImageTagDef struct {
+	Name          string         `json:"name"`
+	PythonVersion string         `json:"pythonVersion,omitempty"`
+	Software      []ImagePackage `json:"software,omitempty"`
+	Packages      []ImagePackage `json:"packages,omitempty"`
+	BuildCommit   string         `json:"buildCommit,omitempty"`
+	Recommended   bool           `json:"recommended"`
+	Outdated      bool           `json:"outdated"`
+}
+
+type ImagePackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type ServingRuntimeDef struct {
+	Name             string   `json:"name"`
+	DisplayName      string   `json:"displayName"`
+	ModelFormats     []string `json:"modelFormats"`
+	APIProtocol      string   `json:"apiProtocol"`
+	ServingPlatforms []string `json:"servingPlatforms"`
+	Accelerators     []string `json:"accelerators"`
 }
 
 func GetImages(ctx context.Context) ([]ImageDef, error) {
@@ -23,82 +51,136 @@ func GetImages(ctx context.Context) ([]ImageDef, error) {
 		return nil, err
 	}
 
-	imagesGVR := schema.GroupVersionResource{Group: "image.openshift.io", Version: "v1", Resource: "imagestreams"}
-	images, err := dyn.Resource(imagesGVR).Namespace("redhat-ods-applications").List(ctx, metav1.ListOptions{
-		LabelSelector: "opendatahub.io/notebook-image=true",
-	})
+	images, err := listNotebookImageStreams(ctx, dyn)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list images: %v", err)
+		return nil, err
 	}
 
 	var result []ImageDef
-	for _, image := range images.Items {
-		annotations := image.GetAnnotations()
-		displayName := annotations["opendatahub.io/notebook-image-name"]
+	for _, image := range images {
+		result = append(result, imageDef(image))
+	}
+	return result, nil
+}
 
-		repoURL, found, err := unstructured.NestedString(image.Object, "status", "dockerImageRepository")
-		if !found || err != nil {
-			repoURL = "URL not available"
-		}
+// GetImageInfo returns the repository, the tag and the image stream name of an image. An empty version
+// resolves to the recommended tag, or the newest one which is not outdated.
+func GetImageInfo(ctx context.Context, displayName, version string) (string, ImageTagDef, string, error) {
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return "", ImageTagDef{}, "", err
+	}
 
-		tagsRaw, _, _ := unstructured.NestedSlice(image.Object, "spec", "tags")
+	images, err := listNotebookImageStreams(ctx, dyn)
+	if err != nil {
+		return "", ImageTagDef{}, "", err
+	}
 
-		var versions []string
-		for _, t := range tagsRaw {
-			tagMap, ok := t.(map[string]interface{})
-			if ok {
-				tagName, _ := tagMap["name"].(string)
-				versions = append(versions, tagName)
+	for _, image := range images {
+		def := imageDef(image)
+		if def.Name != displayName {
+			continue
+		}
+		if version == "" {
+			tag, ok := defaultImageTag(def)
+			if !ok {
+				return "", ImageTagDef{}, "", fmt.Errorf("image %s has no tags", displayName)
+			}
+			return def.URL, tag, image.GetName(), nil
+		}
+		for _, tag := range def.Tags {
+			if tag.Name == version {
+				return def.URL, tag, image.GetName(), nil
 			}
 		}
-
-		result = append(result, ImageDef{
-			Name:     displayName,
-			URL:      repoURL,
-			Versions: versions,
-		})
 	}
-	return result, nil
+	return "", ImageTagDef{}, "", fmt.Errorf("image not found: %s:%s", displayName, version)
 }
 
-func GetImageInfo(ctx context.Context, displayName, version string) (string, string, string, error) {
-	dyn, err := getDynamicClient()
-	if err != nil {
-		return "", "", "", err
+// defaultImageTag picks the tag the dashboard preselects: the recommended one, otherwise
+// the newest tag which is not outdated, otherwise the newest tag
+func defaultImageTag(image ImageDef) (ImageTagDef, bool) {
+	for _, tag := range image.Tags {
+		if tag.Recommended {
+			return tag, true
+		}
 	}
 
-	imagesGVR := schema.GroupVersionResource{Group: "image.openshift.io", Version: "v1", Resource: "imagestreams"}
-	images, err := dyn.Resource(imagesGVR).Namespace("redhat-ods-applications").List(ctx, metav1.ListOptions{
+	var newest, newestCurrent *ImageTagDef
+	for i := range image.Tags {
+		tag := &image.Tags[i]
+		if newest == nil || newerImageTag(*tag, *newest) {
+			newest = tag
+		}
+		if !tag.Outdated && (newestCurrent == nil || newerImageTag(*tag, *newestCurrent)) {
+			newestCurrent = tag
+		}
+	}
+	if newestCurrent != nil {
+		return *newestCurrent, true
+	}
+	if newest != nil {
+		return *newest, true
+	}
+	return ImageTagDef{}, false
+}
+
+func listNotebookImageStreams(ctx context.Context, dyn dynamic.Interface) ([]unstructured.Unstructured, error) {
+	images, err := dyn.Resource(imageStreamsGVR).Namespace(applicationsNamespace).List(ctx, metav1.ListOptions{
 		LabelSelector: "opendatahub.io/notebook-image=true",
 	})
 	if err != nil {
-		return "", "", "", fmt.Errorf("failed to list images: %v", err)
+		return nil, fmt.Errorf("failed to list images: %v", err)
 	}
+	return images.Items, nil
+}
 
-	for _, image := range images.Items {
-		annotations := image.GetAnnotations()
-		if annotations["opendatahub.io/notebook-image-name"] == displayName {
-			repoURL, found, err := unstructured.NestedString(image.Object, "status", "dockerImageRepository")
-			if !found || err != nil {
-				repoURL = "URL not available"
-			}
-			imageName := image.GetName()
-
-			tagsRaw, _, _ := unstructured.NestedSlice(image.Object, "spec", "tags")
-			for _, t := range tagsRaw {
-				tagMap, ok := t.(map[string]interface{})
-				if !ok {
-					continue
-				}
-				tagName, _ := tagMap["name"].(string)
-				if tagName == version {
-					tagAnnotations, _, _ := unstructured.NestedStringMap(tagMap, "annotations")
-					return repoURL, tagAnnotations["opendatahub.io/notebook-build-commit"], imageName, nil
-				}
-			}
+func imageDef(image unstructured.Unstructured) ImageDef {
+	annotations := image.GetAnnotations()
+
+	repoURL, found, err := unstructured.NestedString(image.Object, "status", "dockerImageRepository")
+	if !found || err != nil {
+		repoURL = "URL not available"
+	}
+
+	def := ImageDef{
+		Name:        annotations["opendatahub.io/notebook-image-name"],
+		Description: annotations["opendatahub.io/notebook-image-desc"],
+		URL:         repoURL,
+	}
+	tagsRaw, _, _ := unstructured.NestedSlice(image.Object, "spec", "tags")
+	for _, t := range tagsRaw {
+		tagMap, ok := t.(map[string]interface{})
+		if !ok {
+			continue
 		}
+		tag := imageTagDef(tagMap)
+		def.Versions = append(def.Versions, tag.Name)
+		def.Tags = append(def.Tags, tag)
 	}
-	return "", "", "", fmt.Errorf("image not found: %s:%s", displayName, version)
+	return def
+}
+
+// imageTagDef reads the tag annotations, software and python dependencies are JSON lists
+// like [{"name":"Python","version":"v3.11"}]
+func imageTagDef(tagMap map[string]interface{}) ImageTagDef {
+	tagName, _ := tagMap["name"].(string)
+	tagAnnotations, _, _ := unstructured.NestedStringMap(tagMap, "annotations")
+
+	tag := ImageTagDef{
+		Name:        tagName,
+		BuildCommit: tagAnnotations["opendatahub.io/notebook-build-commit"],
+		Recommended: tagAnnotations["opendatahub.io/workbench-image-recommended"] == "true",
+		Outdated:    tagAnnotations["opendatahub.io/image-tag-outdated"] == "true",
+	}
+	_ = json.Unmarshal([]byte(tagAnnotations["opendatahub.io/notebook-software"]), &tag.Software)
+	_ = json.Unmarshal([]byte(tagAnnotations["opendatahub.io/notebook-python-dependencies"]), &tag.Packages)
+	for _, software := range tag.Software {
+		if strings.EqualFold(software.Name, "Python") {
+			tag.PythonVersion = strings.TrimPrefix(software.Version, "v")
+		}
+	}
+	return tag
 }
 
 func ImagesResourceHandler(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
@@ -122,3 +204,67 @@ func ImagesResourceHandler(ctx context.Context, req *mcp.ReadResourceRequest) (*
 		},
 	}, nil
 }
+
+func GetServingRuntimes(ctx context.Context) ([]ServingRuntimeDef, error) {
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, err
+	}
+
+	templates, err := listServingRuntimeTemplates(ctx, dyn)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []ServingRuntimeDef
+	for _, template := range templates {
+		servingRuntime := templateServingRuntime(template)
+		if servingRuntime == nil {
+			continue
+		}
+		templateAnnotations := template.GetAnnotations()
+		runtimeAnnotations := servingRuntime.GetAnnotations()
+
+		apiProtocol := templateAnnotations["opendatahub.io/apiProtocol"]
+		if apiProtocol == "" {
+			apiProtocol = runtimeAnnotations["opendatahub.io/apiProtocol"]
+		}
+
+		// both annotations hold JSON lists - f.e. ["single"] and ["nvidia.com/gpu"]
+		var platforms, accelerators []string
+		_ = json.Unmarshal([]byte(templateAnnotations["opendatahub.io/modelServingSupport"]), &platforms)
+		_ = json.Unmarshal([]byte(runtimeAnnotations["opendatahub.io/recommended-accelerators"]), &accelerators)
+
+		result = append(result, ServingRuntimeDef{
+			Name:             template.GetName(),
+			DisplayName:      runtimeAnnotations["openshift.io/display-name"],
+			ModelFormats:     servingRuntimeFormats(*servingRuntime),
+			APIProtocol:      apiProtocol,
+			ServingPlatforms: platforms,
+			Accelerators:     accelerators,
+		})
+	}
+	return result, nil
+}
+
+func ServingRuntimesResourceHandler(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	runtimes, err := GetServingRuntimes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonBytes, err := json.Marshal(runtimes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal serving runtimes: %v", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{
+			{
+				URI:      req.Params.URI,
+				MIMEType: "application/json",
+				Text:     string(jsonBytes),
+			},
+		},
+	}, nil
+}
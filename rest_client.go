@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// doJSONRequest sends body encoded as JSON and decodes the JSON response into out,
+// both body and out may be nil
+func doJSONRequest(ctx context.Context, method, url, token string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		bodyBytes, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %v", err)
+		}
+		reader = bytes.NewReader(bodyBytes)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %v", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return doRequest(req, out)
+}
+
+// doRequest sends a prepared request and decodes the JSON response into out
+func doRequest(req *http.Request, out interface{}) error {
+	resp, err := defaultHTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call %s: %v", req.URL, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response of %s: %v", req.URL, err)
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s returned %d: %s", req.Method, req.URL.Path, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	if out == nil || len(respBody) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to parse response of %s: %v", req.URL, err)
+	}
+	return nil
+}
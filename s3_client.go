@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// http client shared by the tools talking to services outside of the kube API
+var defaultHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// s3Client is a minimal client for S3 compatible endpoints (AWS, MinIO, Ceph, ...)
+// signing requests with AWS Signature Version 4 and path style bucket addressing
+type s3Client struct {
+	endpoint  string
+	region    string
+	accessKey string
+	secretKey string
+	http      *http.Client
+}
+
+// s3Error is the error document returned by S3 compatible endpoints
+type s3Error struct {
+	StatusCode int    `xml:"-"`
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+}
+
+func (e *s3Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("unexpected status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+type listBucketResult struct {
+	KeyCount int `xml:"KeyCount"`
+	Contents []struct {
+		Key  string `xml:"Key"`
+		Size int64  `xml:"Size"`
+	} `xml:"Contents"`
+}
+
+func newS3Client(endpoint, region, accessKey, secretKey string) *s3Client {
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+	return &s3Client{
+		endpoint:  strings.TrimSuffix(endpoint, "/"),
+		region:    region,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		http:      defaultHTTPClient,
+	}
+}
+
+// listObjects returns up to maxKeys object keys under prefix in the bucket
+func (c *s3Client) listObjects(ctx context.Context, bucket, prefix string, maxKeys int) ([]string, error) {
+	query := url.Values{}
+	query.Set("list-type", "2")
+	query.Set("max-keys", fmt.Sprintf("%d", maxKeys))
+	if prefix != "" {
+		query.Set("prefix", prefix)
+	}
+
+	body, err := c.do(ctx, http.MethodGet, "/"+bucket, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var result listBucketResult
+	if err := xml.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse bucket listing: %v", err)
+	}
+	keys := make([]string, 0, len(result.Contents))
+	for _, object := range result.Contents {
+		keys = append(keys, object.Key)
+	}
+	return keys, nil
+}
+
+func (c *s3Client) do(ctx context.Context, method, path string, query url.Values) ([]byte, error) {
+	endpoint, err := url.Parse(c.endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %s: %v", c.endpoint, err)
+	}
+	endpoint.Path = strings.TrimSuffix(endpoint.Path, "/") + path
+	endpoint.RawQuery = canonicalQuery(query)
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	c.sign(req, time.Now().UTC())
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+	if resp.StatusCode >= 300 {
+		s3Err := &s3Error{StatusCode: resp.StatusCode}
+		_ = xml.Unmarshal(body, s3Err)
+		return nil, s3Err
+	}
+	return body, nil
+}
+
+// sign adds the AWS Signature Version 4 headers to an unpayloaded request
+func (c *s3Client) sign(req *http.Request, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := hex.EncodeToString(sha256Sum(nil))
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	canonicalHeaders := fmt.Sprintf("host:%s\nx-amz-content-sha256:%s\nx-amz-date:%s\n", req.URL.Host, payloadHash, amzDate)
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", date, c.region)
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(sha256Sum([]byte(canonicalRequest))),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+c.secretKey), date)
+	signingKey = hmacSHA256(signingKey, c.region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", c.accessKey, scope, signedHeaders, signature))
+}
+
+// canonicalQuery encodes the query sorted by key with spaces as %20 as SigV4 requires
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, key := range keys {
+		for _, value := range query[key] {
+			parts = append(parts, url.QueryEscape(key)+"="+strings.ReplaceAll(url.QueryEscape(value), "+", "%20"))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+func sha256Sum(data []byte) []byte {
+	sum := sha256.Sum256(data)
+	return sum[:]
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,444 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+// Lists the persistent volume claims in a project together with the workbenches mounting them
+func ListStorage(ctx context.Context, req *mcp.CallToolRequest, input ListWorkbenchesInput) (*mcp.CallToolResult, ListStorageOutput, error) {
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, ListStorageOutput{}, err
+	}
+
+	pvcs, err := dyn.Resource(pvcGVR).Namespace(input.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, ListStorageOutput{}, fmt.Errorf("failed to list storage: %v", err)
+	}
+
+	mounts, err := workbenchesByClaim(ctx, dyn, input.Namespace)
+	if err != nil {
+		return nil, ListStorageOutput{}, err
+	}
+
+	msg := ""
+	for _, pvc := range pvcs.Items {
+		size, _, _ := unstructured.NestedString(pvc.Object, "status", "capacity", "storage")
+		if size == "" {
+			size, _, _ = unstructured.NestedString(pvc.Object, "spec", "resources", "requests", "storage")
+		}
+		storageClass, _, _ := unstructured.NestedString(pvc.Object, "spec", "storageClassName")
+		if storageClass == "" {
+			storageClass = "default"
+		}
+		phase, _, _ := unstructured.NestedString(pvc.Object, "status", "phase")
+		if phase == "" {
+			phase = "Pending"
+		}
+		accessModes, _, _ := unstructured.NestedStringSlice(pvc.Object, "spec", "accessModes")
+
+		msg += fmt.Sprintf("- %s", pvc.GetName())
+		if displayName := pvc.GetAnnotations()["openshift.io/display-name"]; displayName != "" && displayName != pvc.GetName() {
+			msg += fmt.Sprintf(" (%s)", displayName)
+		}
+		msg += fmt.Sprintf(": %s, class %s, %s, %s", size, storageClass, phase, strings.Join(accessModes, ","))
+		if workbenches := mounts[pvc.GetName()]; len(workbenches) > 0 {
+			msg += fmt.Sprintf(", used by %s", strings.Join(workbenches, ", "))
+		} else {
+			msg += ", not attached"
+		}
+		msg += "\n"
+	}
+	return nil, ListStorageOutput{Storage: msg}, nil
+}
+
+func CreateStorage(ctx context.Context, req *mcp.CallToolRequest, input CreateStorageInput) (*mcp.CallToolResult, StorageOutput, error) {
+	if _, err := resource.ParseQuantity(input.Size); err != nil {
+		return nil, StorageOutput{}, fmt.Errorf("invalid storage size %s: %v", input.Size, err)
+	}
+
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, StorageOutput{}, err
+	}
+
+	displayName := input.DisplayName
+	if displayName == "" {
+		displayName = input.StorageName
+	}
+	pvc := newPersistentVolumeClaim(input.Namespace, input.StorageName, input.Size, input.StorageClass)
+	pvc.SetAnnotations(map[string]string{
+		"openshift.io/display-name": displayName,
+		"openshift.io/description":  input.Description,
+	})
+
+	_, err = dyn.Resource(pvcGVR).Namespace(input.Namespace).Create(ctx, pvc, metav1.CreateOptions{})
+	if err != nil {
+		return nil, StorageOutput{}, fmt.Errorf("failed to create storage %s: %v", input.StorageName, err)
+	}
+
+	return nil, StorageOutput{Message: fmt.Sprintf("Storage %s (%s) was succesfully created!", input.StorageName, input.Size)}, nil
+}
+
+func ExpandStorage(ctx context.Context, req *mcp.CallToolRequest, input ExpandStorageInput) (*mcp.CallToolResult, StorageOutput, error) {
+	newSize, err := resource.ParseQuantity(input.Size)
+	if err != nil {
+		return nil, StorageOutput{}, fmt.Errorf("invalid storage size %s: %v", input.Size, err)
+	}
+
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, StorageOutput{}, err
+	}
+
+	pvc, err := dyn.Resource(pvcGVR).Namespace(input.Namespace).Get(ctx, input.StorageName, metav1.GetOptions{})
+	if err != nil {
+		return nil, StorageOutput{}, fmt.Errorf("failed to get storage %s: %v", input.StorageName, err)
+	}
+
+	currentRaw, _, _ := unstructured.NestedString(pvc.Object, "spec", "resources", "requests", "storage")
+	currentSize, err := resource.ParseQuantity(currentRaw)
+	if err != nil {
+		return nil, StorageOutput{}, fmt.Errorf("invalid current size %s of storage %s: %v", currentRaw, input.StorageName, err)
+	}
+	if newSize.Cmp(currentSize) <= 0 {
+		return nil, StorageOutput{}, fmt.Errorf("storage %s can only be expanded, current size is %s", input.StorageName, currentRaw)
+	}
+
+	storageClassName, _, _ := unstructured.NestedString(pvc.Object, "spec", "storageClassName")
+	storageClass, err := getStorageClass(ctx, dyn, storageClassName)
+	if err != nil {
+		return nil, StorageOutput{}, err
+	}
+	if allowed, _, _ := unstructured.NestedBool(storageClass.Object, "allowVolumeExpansion"); !allowed {
+		return nil, StorageOutput{}, fmt.Errorf("storage class %s does not allow volume expansion", storageClass.GetName())
+	}
+
+	patch := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"resources": map[string]interface{}{
+				"requests": map[string]interface{}{
+					"storage": input.Size,
+				},
+			},
+		},
+	}
+	patchBytes, err := json.Marshal(patch)
+	if err != nil {
+		return nil, StorageOutput{}, fmt.Errorf("failed to marshal patch: %v", err)
+	}
+
+	_, err = dyn.Resource(pvcGVR).Namespace(input.Namespace).Patch(ctx, input.StorageName, k8stypes.MergePatchType, patchBytes, metav1.PatchOptions{})
+	if err != nil {
+		return nil, StorageOutput{}, fmt.Errorf("failed to expand storage %s: %v", input.StorageName, err)
+	}
+
+	return nil, StorageOutput{Message: fmt.Sprintf("Storage %s was expanded from %s to %s", input.StorageName, currentRaw, input.Size)}, nil
+}
+
+func DeleteStorage(ctx context.Context, req *mcp.CallToolRequest, input StorageInput) (*mcp.CallToolResult, StorageOutput, error) {
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, StorageOutput{}, err
+	}
+
+	mounts, err := workbenchesByClaim(ctx, dyn, input.Namespace)
+	if err != nil {
+		return nil, StorageOutput{}, err
+	}
+	if workbenches := mounts[input.StorageName]; len(workbenches) > 0 {
+		return nil, StorageOutput{}, fmt.Errorf("storage %s is still attached to workbenches: %s", input.StorageName, strings.Join(workbenches, ", "))
+	}
+
+	err = dyn.Resource(pvcGVR).Namespace(input.Namespace).Delete(ctx, input.StorageName, metav1.DeleteOptions{})
+	if err != nil {
+		return nil, StorageOutput{}, fmt.Errorf("failed to delete storage %s: %v", input.StorageName, err)
+	}
+
+	return nil, StorageOutput{Message: fmt.Sprintf("Storage %s was deleted", input.StorageName)}, nil
+}
+
+// workbenchesByClaim maps every claim name to the workbenches in the namespace which mount it
+func workbenchesByClaim(ctx context.Context, dyn dynamic.Interface, namespace string) (map[string][]string, error) {
+	notebooks, err := dyn.Resource(workbenchesGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workbenches: %v", err)
+	}
+
+	mounts := map[string][]string{}
+	for _, nb := range notebooks.Items {
+		for _, claimName := range workbenchClaims(nb) {
+			mounts[claimName] = append(mounts[claimName], nb.GetName())
+		}
+	}
+	return mounts, nil
+}
+
+func workbenchClaims(nb unstructured.Unstructured) []string {
+	volumes, _, _ := unstructured.NestedSlice(nb.Object, "spec", "template", "spec", "volumes")
+
+	var claims []string
+	for _, v := range volumes {
+		volume, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		claimName, _, _ := unstructured.NestedString(volume, "persistentVolumeClaim", "claimName")
+		if claimName != "" {
+			claims = append(claims, claimName)
+		}
+	}
+	return claims
+}
+
+// getStorageClass returns the named storage class or the cluster default when name is empty
+func getStorageClass(ctx context.Context, dyn dynamic.Interface, name string) (*unstructured.Unstructured, error) {
+	if name != "" {
+		storageClass, err := dyn.Resource(storageClassesGVR).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get storage class %s: %v", name, err)
+		}
+		return storageClass, nil
+	}
+
+	storageClasses, err := dyn.Resource(storageClassesGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list storage classes: %v", err)
+	}
+	for _, storageClass := range storageClasses.Items {
+		if storageClass.GetAnnotations()["storageclass.kubernetes.io/is-default-class"] == "true" {
+			return &storageClass, nil
+		}
+	}
+	return nil, fmt.Errorf("no default storage class found")
+}
+
+// Mounts an existing persistent volume claim into an existing workbench
+func AttachStorage(ctx context.Context, req *mcp.CallToolRequest, input AttachStorageInput) (*mcp.CallToolResult, StorageOutput, error) {
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, StorageOutput{}, err
+	}
+
+	notebook, err := dyn.Resource(workbenchesGVR).Namespace(input.Namespace).Get(ctx, input.WorkbenchName, metav1.GetOptions{})
+	if err != nil {
+		return nil, StorageOutput{}, fmt.Errorf("failed to get workbench %s: %v", input.WorkbenchName, err)
+	}
+	for _, claimName := range workbenchClaims(*notebook) {
+		if claimName == input.StorageName {
+			return nil, StorageOutput{Message: fmt.Sprintf("Storage %s is already attached to workbench %s", input.StorageName, input.WorkbenchName)}, nil
+		}
+	}
+	if err := checkStorageAvailable(ctx, dyn, input.Namespace, input.StorageName, input.WorkbenchName); err != nil {
+		return nil, StorageOutput{}, err
+	}
+
+	containers, _, _ := unstructured.NestedSlice(notebook.Object, "spec", "template", "spec", "containers")
+	container := workbenchContainer(containers, input.WorkbenchName)
+	if container == nil {
+		return nil, StorageOutput{}, fmt.Errorf("workbench %s has no containers", input.WorkbenchName)
+	}
+	volumes, _, _ := unstructured.NestedSlice(notebook.Object, "spec", "template", "spec", "volumes")
+	volumeName := storageVolumeName(volumes)
+	mount := storageVolumeMount(volumeName, input.StorageName, input.MountPath)
+	volumeMounts, _, _ := unstructured.NestedSlice(container, "volumeMounts")
+	if err := checkMountPath(volumeMounts, mount["mountPath"].(string), input.WorkbenchName); err != nil {
+		return nil, StorageOutput{}, err
+	}
+	container["volumeMounts"] = append(volumeMounts, mount)
+
+	volumes = append(volumes, storageVolume(volumeName, input.StorageName))
+	if err := unstructured.SetNestedSlice(notebook.Object, containers, "spec", "template", "spec", "containers"); err != nil {
+		return nil, StorageOutput{}, fmt.Errorf("failed to set containers: %v", err)
+	}
+	if err := unstructured.SetNestedSlice(notebook.Object, volumes, "spec", "template", "spec", "volumes"); err != nil {
+		return nil, StorageOutput{}, fmt.Errorf("failed to set volumes: %v", err)
+	}
+
+	_, err = dyn.Resource(workbenchesGVR).Namespace(input.Namespace).Update(ctx, notebook, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, StorageOutput{}, fmt.Errorf("failed to update workbench %s: %v", input.WorkbenchName, err)
+	}
+
+	return nil, StorageOutput{Message: fmt.Sprintf("Storage %s was attached to workbench %s at %s", input.StorageName, input.WorkbenchName, mount["mountPath"])}, nil
+}
+
+// Unmounts a persistent volume claim from a workbench, the home volume cannot be detached
+func DetachStorage(ctx context.Context, req *mcp.CallToolRequest, input DetachStorageInput) (*mcp.CallToolResult, StorageOutput, error) {
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, StorageOutput{}, err
+	}
+
+	notebook, err := dyn.Resource(workbenchesGVR).Namespace(input.Namespace).Get(ctx, input.WorkbenchName, metav1.GetOptions{})
+	if err != nil {
+		return nil, StorageOutput{}, fmt.Errorf("failed to get workbench %s: %v", input.WorkbenchName, err)
+	}
+
+	volumes, _, _ := unstructured.NestedSlice(notebook.Object, "spec", "template", "spec", "volumes")
+	var keptVolumes []interface{}
+	volumeName := ""
+	for _, v := range volumes {
+		volume, _ := v.(map[string]interface{})
+		if claimName, _, _ := unstructured.NestedString(volume, "persistentVolumeClaim", "claimName"); claimName == input.StorageName {
+			volumeName, _ = volume["name"].(string)
+			continue
+		}
+		keptVolumes = append(keptVolumes, v)
+	}
+	if volumeName == "" {
+		return nil, StorageOutput{Message: fmt.Sprintf("Storage %s is not attached to workbench %s", input.StorageName, input.WorkbenchName)}, nil
+	}
+
+	containers, _, _ := unstructured.NestedSlice(notebook.Object, "spec", "template", "spec", "containers")
+	for _, c := range containers {
+		container, _ := c.(map[string]interface{})
+		volumeMounts, _, _ := unstructured.NestedSlice(container, "volumeMounts")
+		var keptMounts []interface{}
+		for _, m := range volumeMounts {
+			mount, _ := m.(map[string]interface{})
+			if mount["name"] != volumeName {
+				keptMounts = append(keptMounts, m)
+				continue
+			}
+			if mountPath, _ := mount["mountPath"].(string); strings.TrimSuffix(mountPath, "/") == "/opt/app-root/src" {
+				return nil, StorageOutput{}, fmt.Errorf("storage %s is the home volume of workbench %s and cannot be detached", input.StorageName, input.WorkbenchName)
+			}
+		}
+		container["volumeMounts"] = keptMounts
+	}
+
+	if err := unstructured.SetNestedSlice(notebook.Object, containers, "spec", "template", "spec", "containers"); err != nil {
+		return nil, StorageOutput{}, fmt.Errorf("failed to set containers: %v", err)
+	}
+	if err := unstructured.SetNestedSlice(notebook.Object, keptVolumes, "spec", "template", "spec", "volumes"); err != nil {
+		return nil, StorageOutput{}, fmt.Errorf("failed to set volumes: %v", err)
+	}
+
+	_, err = dyn.Resource(workbenchesGVR).Namespace(input.Namespace).Update(ctx, notebook, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, StorageOutput{}, fmt.Errorf("failed to update workbench %s: %v", input.WorkbenchName, err)
+	}
+
+	return nil, StorageOutput{Message: fmt.Sprintf("Storage %s was detached from workbench %s", input.StorageName, input.WorkbenchName)}, nil
+}
+
+// checkStorageAvailable makes sure the claim exists and a ReadWriteOnce claim
+// is not mounted by another running workbench
+func checkStorageAvailable(ctx context.Context, dyn dynamic.Interface, namespace, claimName, workbenchName string) error {
+	pvc, err := dyn.Resource(pvcGVR).Namespace(namespace).Get(ctx, claimName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get storage %s: %v", claimName, err)
+	}
+
+	accessModes, _, _ := unstructured.NestedStringSlice(pvc.Object, "spec", "accessModes")
+	readWriteOnce := false
+	for _, mode := range accessModes {
+		if mode == "ReadWriteOnce" || mode == "ReadWriteOncePod" {
+			readWriteOnce = true
+		}
+	}
+	if !readWriteOnce {
+		return nil
+	}
+
+	notebooks, err := dyn.Resource(workbenchesGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list workbenches: %v", err)
+	}
+	for _, nb := range notebooks.Items {
+		if nb.GetName() == workbenchName || workbenchState(nb) == "stopped" {
+			continue
+		}
+		for _, claim := range workbenchClaims(nb) {
+			if claim == claimName {
+				return fmt.Errorf("storage %s is ReadWriteOnce and already mounted by running workbench %s", claimName, nb.GetName())
+			}
+		}
+	}
+	return nil
+}
+
+// storageVolume mounts a claim under a generated volume name, claim names may be longer
+// than volume names allow or contain dots
+func storageVolume(volumeName, claimName string) map[string]interface{} {
+	return map[string]interface{}{
+		"name": volumeName,
+		"persistentVolumeClaim": map[string]interface{}{
+			"claimName": claimName,
+		},
+	}
+}
+
+func storageVolumeMount(volumeName, claimName, mountPath string) map[string]interface{} {
+	if mountPath == "" {
+		mountPath = "/opt/app-root/src/" + claimName
+	}
+	return map[string]interface{}{
+		"mountPath": mountPath,
+		"name":      volumeName,
+	}
+}
+
+// checkMountPath rejects relative mount paths and paths already used by one of volumeMounts
+func checkMountPath(volumeMounts []interface{}, mountPath, workbenchName string) error {
+	if !path.IsAbs(mountPath) {
+		return fmt.Errorf("mount path %s has to be absolute", mountPath)
+	}
+	for _, m := range volumeMounts {
+		existing, ok := m.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if existingPath, _ := existing["mountPath"].(string); path.Clean(existingPath) == path.Clean(mountPath) {
+			return fmt.Errorf("mount path %s is already used in workbench %s", mountPath, workbenchName)
+		}
+	}
+	return nil
+}
+
+// storageVolumeName returns the first storage-<n> name not used by volumes
+func storageVolumeName(volumes []interface{}) string {
+	used := map[string]bool{}
+	for _, v := range volumes {
+		if volume, ok := v.(map[string]interface{}); ok {
+			name, _ := volume["name"].(string)
+			used[name] = true
+		}
+	}
+	for n := 1; ; n++ {
+		name := fmt.Sprintf("storage-%d", n)
+		if !used[name] {
+			return name
+		}
+	}
+}
+
+// workbenchContainer returns the notebook container named after the workbench, or the first one
+func workbenchContainer(containers []interface{}, workbenchName string) map[string]interface{} {
+	var first map[string]interface{}
+	for _, c := range containers {
+		container, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if container["name"] == workbenchName {
+			return container
+		}
+		if first == nil {
+			first = container
+		}
+	}
+	return first
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func newUnstructuredPVC(name, namespace, size, storageClass string) *unstructured.Unstructured {
+	u := newPersistentVolumeClaim(namespace, name, size, storageClass)
+	_ = unstructured.SetNestedField(u.Object, "Bound", "status", "phase")
+	return u
+}
+
+func newUnstructuredStorageClass(name string, allowExpansion bool) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"allowVolumeExpansion": allowExpansion,
+	}}
+	u.SetGroupVersionKind(storageClassesGVR.GroupVersion().WithKind("StorageClass"))
+	u.SetName(name)
+	return u
+}
+
+func mountClaim(nb *unstructured.Unstructured, claimName string) {
+	volumes, _, _ := unstructured.NestedSlice(nb.Object, "spec", "template", "spec", "volumes")
+	volumes = append(volumes, map[string]interface{}{
+		"name":                  claimName,
+		"persistentVolumeClaim": map[string]interface{}{"claimName": claimName},
+	})
+	_ = unstructured.SetNestedSlice(nb.Object, volumes, "spec", "template", "spec", "volumes")
+}
+
+func TestStorageTools(t *testing.T) {
+	orig := getDynamicClient
+	defer func() { getDynamicClient = orig }()
+
+	workbench := newUnstructuredWorkbench("wb-1", "ns1")
+	mountClaim(workbench, "wb-1")
+
+	scheme := runtime.NewScheme()
+	client := dynamicfake.NewSimpleDynamicClient(scheme,
+		workbench,
+		newUnstructuredPVC("wb-1", "ns1", "10Gi", "expandable"),
+		newUnstructuredPVC("data", "ns1", "20Gi", "fixed"),
+		newUnstructuredStorageClass("expandable", true),
+		newUnstructuredStorageClass("fixed", false),
+	)
+	getDynamicClient = func() (dynamic.Interface, error) {
+		return client, nil
+	}
+	ctx := context.Background()
+
+	_, list, err := ListStorage(ctx, nil, ListWorkbenchesInput{Namespace: "ns1"})
+	if err != nil {
+		t.Fatalf("ListStorage returned error: %v", err)
+	}
+	if !strings.Contains(list.Storage, "- wb-1: 10Gi, class expandable, Bound, ReadWriteOnce, used by wb-1\n") {
+		t.Errorf("expected wb-1 storage in output, got: %q", list.Storage)
+	}
+	if !strings.Contains(list.Storage, "- data: 20Gi, class fixed, Bound, ReadWriteOnce, not attached\n") {
+		t.Errorf("expected data storage in output, got: %q", list.Storage)
+	}
+
+	if _, _, err := ExpandStorage(ctx, nil, ExpandStorageInput{Namespace: "ns1", StorageName: "data", Size: "30Gi"}); err == nil {
+		t.Errorf("expected error when storage class does not allow expansion")
+	}
+	if _, _, err := ExpandStorage(ctx, nil, ExpandStorageInput{Namespace: "ns1", StorageName: "wb-1", Size: "5Gi"}); err == nil {
+		t.Errorf("expected error when shrinking storage")
+	}
+	_, out, err := ExpandStorage(ctx, nil, ExpandStorageInput{Namespace: "ns1", StorageName: "wb-1", Size: "20Gi"})
+	if err != nil {
+		t.Fatalf("ExpandStorage returned error: %v", err)
+	}
+	if out.Message != "Storage wb-1 was expanded from 10Gi to 20Gi" {
+		t.Errorf("unexpected message: %q", out.Message)
+	}
+	expanded, _ := client.Resource(pvcGVR).Namespace("ns1").Get(ctx, "wb-1", metav1.GetOptions{})
+	if size, _, _ := unstructured.NestedString(expanded.Object, "spec", "resources", "requests", "storage"); size != "20Gi" {
+		t.Errorf("expected requested size 20Gi, got: %q", size)
+	}
+
+	if _, _, err := DeleteStorage(ctx, nil, StorageInput{Namespace: "ns1", StorageName: "wb-1"}); err == nil {
+		t.Errorf("expected error when deleting attached storage")
+	}
+	_, out, err = DeleteStorage(ctx, nil, StorageInput{Namespace: "ns1", StorageName: "data"})
+	if err != nil {
+		t.Fatalf("DeleteStorage returned error: %v", err)
+	}
+	if out.Message != "Storage data was deleted" {
+		t.Errorf("unexpected message: %q", out.Message)
+	}
+}
+
+func TestAttachDetachStorage(t *testing.T) {
+	orig := getDynamicClient
+	defer func() { getDynamicClient = orig }()
+
+	newWorkbench := func(name string) *unstructured.Unstructured {
+		nb := newUnstructuredWorkbench(name, "ns1")
+		_ = unstructured.SetNestedSlice(nb.Object, []interface{}{
+			map[string]interface{}{
+				"name": name,
+				"volumeMounts": []interface{}{
+					map[string]interface{}{"name": "storage-volume", "mountPath": "/opt/app-root/src/"},
+				},
+			},
+		}, "spec", "template", "spec", "containers")
+		_ = unstructured.SetNestedSlice(nb.Object, []interface{}{
+			map[string]interface{}{
+				"name":                  "storage-volume",
+				"persistentVolumeClaim": map[string]interface{}{"claimName": name},
+			},
+		}, "spec", "template", "spec", "volumes")
+		return nb
+	}
+	runningWorkbench := newWorkbench("wb-1")
+	mountClaim(runningWorkbench, "shared")
+
+	scheme := runtime.NewScheme()
+	client := dynamicfake.NewSimpleDynamicClient(scheme,
+		runningWorkbench,
+		newWorkbench("wb-2"),
+		newUnstructuredPVC("wb-2", "ns1", "10Gi", ""),
+		newUnstructuredPVC("shared", "ns1", "10Gi", ""),
+		newUnstructuredPVC("data", "ns1", "10Gi", ""),
+		newUnstructuredPVC("data.v1", "ns1", "10Gi", ""),
+	)
+	getDynamicClient = func() (dynamic.Interface, error) {
+		return client, nil
+	}
+	ctx := context.Background()
+
+	if _, _, err := AttachStorage(ctx, nil, AttachStorageInput{Namespace: "ns1", WorkbenchName: "wb-2", StorageName: "shared"}); err == nil {
+		t.Errorf("expected error when attaching ReadWriteOnce storage mounted by a running workbench")
+	}
+
+	if _, _, err := AttachStorage(ctx, nil, AttachStorageInput{Namespace: "ns1", WorkbenchName: "wb-2", StorageName: "data", MountPath: "/opt/app-root/src"}); err == nil {
+		t.Errorf("expected error when mounting over the home directory")
+	}
+
+	_, out, err := AttachStorage(ctx, nil, AttachStorageInput{Namespace: "ns1", WorkbenchName: "wb-2", StorageName: "data"})
+	if err != nil {
+		t.Fatalf("AttachStorage returned error: %v", err)
+	}
+	if out.Message != "Storage data was attached to workbench wb-2 at /opt/app-root/src/data" {
+		t.Errorf("unexpected message: %q", out.Message)
+	}
+	updated, _ := client.Resource(workbenchesGVR).Namespace("ns1").Get(ctx, "wb-2", metav1.GetOptions{})
+	if claims := workbenchClaims(*updated); len(claims) != 2 || claims[1] != "data" {
+		t.Errorf("expected data volume on wb-2, got: %v", claims)
+	}
+
+	if _, _, err := AttachStorage(ctx, nil, AttachStorageInput{Namespace: "ns1", WorkbenchName: "wb-2", StorageName: "data.v1"}); err != nil {
+		t.Fatalf("AttachStorage returned error for dotted claim name: %v", err)
+	}
+	updated, _ = client.Resource(workbenchesGVR).Namespace("ns1").Get(ctx, "wb-2", metav1.GetOptions{})
+	volumes, _, _ := unstructured.NestedSlice(updated.Object, "spec", "template", "spec", "volumes")
+	names := []interface{}{}
+	for _, v := range volumes {
+		names = append(names, v.(map[string]interface{})["name"])
+	}
+	if len(names) != 3 || names[1] != "storage-1" || names[2] != "storage-2" {
+		t.Errorf("expected generated volume names storage-1 and storage-2, got: %v", names)
+	}
+	if _, _, err := DetachStorage(ctx, nil, DetachStorageInput{Namespace: "ns1", WorkbenchName: "wb-2", StorageName: "data.v1"}); err != nil {
+		t.Fatalf("DetachStorage returned error for dotted claim name: %v", err)
+	}
+
+	if _, _, err := DetachStorage(ctx, nil, DetachStorageInput{Namespace: "ns1", WorkbenchName: "wb-2", StorageName: "wb-2"}); err == nil {
+		t.Errorf("expected error when detaching the home volume")
+	}
+
+	_, out, err = DetachStorage(ctx, nil, DetachStorageInput{Namespace: "ns1", WorkbenchName: "wb-2", StorageName: "data"})
+	if err != nil {
+		t.Fatalf("DetachStorage returned error: %v", err)
+	}
+	if out.Message != "Storage data was detached from workbench wb-2" {
+		t.Errorf("unexpected message: %q", out.Message)
+	}
+	updated, _ = client.Resource(workbenchesGVR).Namespace("ns1").Get(ctx, "wb-2", metav1.GetOptions{})
+	if claims := workbenchClaims(*updated); len(claims) != 1 {
+		t.Errorf("expected only home volume on wb-2, got: %v", claims)
+	}
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	k8stypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
@@ -19,6 +21,17 @@ var getClientSet = func() (kubernetes.Interface, error) { return LogIntoClusterC
 
 var getDynamicClient = func() (dynamic.Interface, error) { return LogIntoClusterDynamic() }
 
+var getClusterToken = func() (string, error) { return LogIntoClusterToken() }
+
+var readWorkbenchFile = func(ctx context.Context, namespace, workbench, path string) ([]byte, error) {
+	return execInWorkbench(ctx, namespace, workbench, []string{"cat", path})
+}
+
+var resolveWorkbenchPath = func(ctx context.Context, namespace, workbench, path string) (string, error) {
+	out, err := execInWorkbench(ctx, namespace, workbench, []string{"realpath", "-e", "--", path})
+	return strings.TrimSpace(string(out)), err
+}
+
 func ListPods(ctx context.Context, req *mcp.CallToolRequest, input ListWorkbenchesInput) (*mcp.CallToolResult, PodsOutput, error) {
 	clientset, err := getClientSet()
 	if err != nil {
@@ -58,12 +71,87 @@ func ListWorkbenches(ctx context.Context, req *mcp.CallToolRequest, input ListWo
 	return nil, ListWorkbenchesResult{Workbenches: msg}, nil
 }
 
-func ListAllWorkbenches(ctx context.Context, req *mcp.CallToolRequest, input ListWorkbenchesInput) (*mcp.CallToolResult, ListWorkbenchesResult, error) {
-	_, workbenches, err := ListWorkbenches(ctx, req, ListWorkbenchesInput{Namespace: ""})
+// Lists workbenches across all namespaces grouped by the project they belong to
+func ListAllWorkbenches(ctx context.Context, req *mcp.CallToolRequest, input ListAllWorkbenchesInput) (*mcp.CallToolResult, ListWorkbenchesResult, error) {
+	dyn, err := getDynamicClient()
 	if err != nil {
 		return nil, ListWorkbenchesResult{}, err
 	}
-	return nil, ListWorkbenchesResult{Workbenches: workbenches.Workbenches}, nil
+
+	listOptions := metav1.ListOptions{}
+	if input.DashboardOnly {
+		listOptions.LabelSelector = "opendatahub.io/dashboard=true"
+	}
+	// projects only provide display names unless the list is limited to data science projects
+	displayNames := map[string]string{}
+	projects, err := dyn.Resource(projectsGVR).List(ctx, listOptions)
+	if err != nil && input.DashboardOnly {
+		return nil, ListWorkbenchesResult{}, fmt.Errorf("failed to list projects: %v", err)
+	}
+	if err == nil {
+		for _, project := range projects.Items {
+			displayNames[project.GetName()] = project.GetAnnotations()["openshift.io/display-name"]
+		}
+	}
+
+	notebooks, err := dyn.Resource(workbenchesGVR).Namespace("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, ListWorkbenchesResult{}, fmt.Errorf("failed to list workbenches: %v", err)
+	}
+
+	byNamespace := map[string][]unstructured.Unstructured{}
+	for _, nb := range notebooks.Items {
+		if _, ok := displayNames[nb.GetNamespace()]; input.DashboardOnly && !ok {
+			continue
+		}
+		byNamespace[nb.GetNamespace()] = append(byNamespace[nb.GetNamespace()], nb)
+	}
+
+	namespaces := make([]string, 0, len(byNamespace))
+	for ns := range byNamespace {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+
+	msg := ""
+	for _, ns := range namespaces {
+		counts := map[string]int{}
+		lines := ""
+		for _, nb := range byNamespace[ns] {
+			state := workbenchState(nb)
+			counts[state]++
+			lines += fmt.Sprintf("- %s (%s)\n", nb.GetName(), state)
+		}
+
+		title := ns
+		if displayName := displayNames[ns]; displayName != "" && displayName != ns {
+			title = fmt.Sprintf("%s (%s)", displayName, ns)
+		}
+		var states []string
+		for _, state := range []string{"running", "starting", "stopped"} {
+			if counts[state] > 0 {
+				states = append(states, fmt.Sprintf("%d %s", counts[state], state))
+			}
+		}
+		count := fmt.Sprintf("%d workbenches", len(byNamespace[ns]))
+		if len(byNamespace[ns]) == 1 {
+			count = "1 workbench"
+		}
+		msg += fmt.Sprintf("Project: %s - %s (%s)\n%s", title, count, strings.Join(states, ", "), lines)
+	}
+	return nil, ListWorkbenchesResult{Workbenches: msg}, nil
+}
+
+// workbenchState reports whether the notebook is stopped, running or still starting up
+func workbenchState(nb unstructured.Unstructured) string {
+	if _, ok := nb.GetAnnotations()["kubeflow-resource-stopped"]; ok {
+		return "stopped"
+	}
+	readyReplicas, _, _ := unstructured.NestedInt64(nb.Object, "status", "readyReplicas")
+	if readyReplicas > 0 {
+		return "running"
+	}
+	return "starting"
 }
 
 func IsWorkbenchStopped(ctx context.Context, dyn dynamic.Interface, namespace, workbenchName string) (bool, error) {
@@ -134,7 +222,42 @@ func ListImages(ctx context.Context, req *mcp.CallToolRequest, input ListWorkben
 
 	msg := ""
 	for _, image := range images {
-		msg += fmt.Sprintf("Image: %s\n URL: %s\n Versions: %s\n", image.Name, image.URL, strings.Join(image.Versions, "\n"))
+		msg += fmt.Sprintf("Image: %s\n URL: %s\n", image.Name, image.URL)
+		if image.Description != "" {
+			msg += fmt.Sprintf(" Description: %s\n", image.Description)
+		}
+		msg += " Versions:\n"
+		for _, tag := range image.Tags {
+			msg += fmt.Sprintf(" - %s\n", imageTagSummary(tag))
+		}
 	}
 	return nil, ListImagesOutput{Images: msg}, nil
 }
+
+// imageTagSummary describes a tag on one line - f.e. 2024.2 (recommended): Python 3.11, PyTorch 2.4
+func imageTagSummary(tag ImageTagDef) string {
+	summary := tag.Name
+	if tag.Recommended {
+		summary += " (recommended)"
+	}
+	if tag.Outdated {
+		summary += " (outdated)"
+	}
+
+	var packages []string
+	if tag.PythonVersion != "" {
+		packages = append(packages, "Python "+tag.PythonVersion)
+	}
+	for _, software := range tag.Software {
+		if !strings.EqualFold(software.Name, "Python") {
+			packages = append(packages, software.Name+" "+software.Version)
+		}
+	}
+	for _, pkg := range tag.Packages {
+		packages = append(packages, pkg.Name+" "+pkg.Version)
+	}
+	if len(packages) > 0 {
+		summary += ": " + strings.Join(packages, ", ")
+	}
+	return summary
+}
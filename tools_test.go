@@ -5,6 +5,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -13,10 +14,12 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 func TestListPods_Success(t *testing.T) {
@@ -92,13 +95,34 @@ func TestListWorkbenches(t *testing.T) {
 	}
 }
 
+func newUnstructuredProject(name, displayName string, dashboard bool) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(projectsGVR.GroupVersion().WithKind("Project"))
+	u.SetName(name)
+	u.SetAnnotations(map[string]string{"openshift.io/display-name": displayName})
+	if dashboard {
+		u.SetLabels(map[string]string{"opendatahub.io/dashboard": "true"})
+	}
+	return u
+}
+
 func TestListAllWorkbenches(t *testing.T) {
 	orig := getDynamicClient
 	defer func() { getDynamicClient = orig }()
 
+	stoppedWorkbench := newUnstructuredWorkbench("wb-3", "ns1")
+	stoppedWorkbench.SetAnnotations(map[string]string{
+		"kubeflow-resource-stopped": time.Now().UTC().Format(time.RFC3339),
+	})
+	runningWorkbench := newUnstructuredWorkbench("wb-1", "ns1")
+	_ = unstructured.SetNestedField(runningWorkbench.Object, int64(1), "status", "readyReplicas")
+
 	scheme := runtime.NewScheme()
 	client := dynamicfake.NewSimpleDynamicClient(scheme,
-		newUnstructuredWorkbench("wb-1", "ns1"),
+		newUnstructuredProject("ns1", "Fraud Detection", true),
+		newUnstructuredProject("ns2", "", false),
+		runningWorkbench,
+		stoppedWorkbench,
 		newUnstructuredWorkbench("wb-2", "ns2"),
 	)
 
@@ -106,16 +130,44 @@ func TestListAllWorkbenches(t *testing.T) {
 		return client, nil
 	}
 
-	_, out, err := ListAllWorkbenches(context.Background(), nil, ListWorkbenchesInput{Namespace: ""})
+	_, out, err := ListAllWorkbenches(context.Background(), nil, ListAllWorkbenchesInput{})
 	if err != nil {
 		t.Fatalf("ListAllWorkbenches returned error: %v", err)
 	}
 
-	if !strings.Contains(out.Workbenches, "- wb-1\n") {
+	if !strings.Contains(out.Workbenches, "Project: Fraud Detection (ns1) - 2 workbenches (1 running, 1 stopped)\n") {
+		t.Errorf("expected ns1 summary in output, got: %q", out.Workbenches)
+	}
+	if !strings.Contains(out.Workbenches, "- wb-1 (running)\n") {
 		t.Errorf("expected wb-1 in output, got: %q", out.Workbenches)
 	}
-	if !strings.Contains(out.Workbenches, "- wb-2\n") {
-		t.Errorf("expected wb-2 in output, got: %q", out.Workbenches)
+	if !strings.Contains(out.Workbenches, "- wb-3 (stopped)\n") {
+		t.Errorf("expected wb-3 in output, got: %q", out.Workbenches)
+	}
+	if !strings.Contains(out.Workbenches, "Project: ns2 - 1 workbench (1 starting)\n- wb-2 (starting)\n") {
+		t.Errorf("expected wb-2 under ns2 in output, got: %q", out.Workbenches)
+	}
+
+	_, out, err = ListAllWorkbenches(context.Background(), nil, ListAllWorkbenchesInput{DashboardOnly: true})
+	if err != nil {
+		t.Fatalf("ListAllWorkbenches returned error: %v", err)
+	}
+	if strings.Contains(out.Workbenches, "wb-2") {
+		t.Errorf("did not expect wb-2 from non dashboard namespace, got: %q", out.Workbenches)
+	}
+	// without the dashboard filter a forbidden project list only drops the display names
+	client.PrependReactor("list", "projects", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, fmt.Errorf("projects are forbidden")
+	})
+	_, out, err = ListAllWorkbenches(context.Background(), nil, ListAllWorkbenchesInput{})
+	if err != nil {
+		t.Fatalf("ListAllWorkbenches returned error when projects cannot be listed: %v", err)
+	}
+	if !strings.Contains(out.Workbenches, "Project: ns1 - 2 workbenches (1 running, 1 stopped)\n") {
+		t.Errorf("expected ns1 summary without display name, got: %q", out.Workbenches)
+	}
+	if _, _, err := ListAllWorkbenches(context.Background(), nil, ListAllWorkbenchesInput{DashboardOnly: true}); err == nil {
+		t.Errorf("expected error when projects cannot be listed for dashboard only workbenches")
 	}
 }
 
@@ -175,10 +227,301 @@ func TestChangeWorkbenchStatus(t *testing.T) {
 	}
 }
 
-// TODO
+func newUnstructuredImageStream(name, displayName string, tags ...string) *unstructured.Unstructured {
+	var specTags []interface{}
+	for _, tag := range tags {
+		specTags = append(specTags, map[string]interface{}{
+			"name": tag,
+			"annotations": map[string]interface{}{
+				"opendatahub.io/notebook-build-commit": "abc123",
+			},
+		})
+	}
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec":   map[string]interface{}{"tags": specTags},
+		"status": map[string]interface{}{"dockerImageRepository": "image-registry/redhat-ods-applications/" + name},
+	}}
+	u.SetGroupVersionKind(schema.GroupVersionKind{Group: "image.openshift.io", Version: "v1", Kind: "ImageStream"})
+	u.SetName(name)
+	u.SetNamespace("redhat-ods-applications")
+	u.SetLabels(map[string]string{"opendatahub.io/notebook-image": "true"})
+	u.SetAnnotations(map[string]string{"opendatahub.io/notebook-image-name": displayName})
+	return u
+}
+
 func TestCreateWorkbench(t *testing.T) {
+	orig := getDynamicClient
+	defer func() { getDynamicClient = orig }()
+
+	scheme := runtime.NewScheme()
+	client := dynamicfake.NewSimpleDynamicClient(scheme,
+		newUnstructuredImageStream("s2i-minimal-notebook", "Jupyter | Minimal | CPU | Python 3.12", "2025.1"),
+		newUnstructuredWorkbench("other", "ns1"),
+		newUnstructuredPVC("data.v1", "ns1", "10Gi", ""),
+		newUnstructuredConnection("models", "ns1", "s3", nil),
+	)
+	getDynamicClient = func() (dynamic.Interface, error) {
+		return client, nil
+	}
+	ctx := context.Background()
+
+	input := CreateWorkbenchInput{
+		Namespace:         "ns1",
+		WorkbenchName:     "wb-1",
+		ImageDisplayName:  "Jupyter | Minimal | CPU | Python 3.12",
+		ImageTag:          "2025.1",
+		AdditionalStorage: []StorageMount{{StorageName: "data.v1"}},
+		Connections:       []string{"models"},
+		EnvVars:           []EnvVar{{Name: "EPOCHS", Value: "3"}},
+	}
+	_, out, err := CreateWorkbench(ctx, nil, input)
+	if err != nil {
+		t.Fatalf("CreateWorkbench returned error: %v", err)
+	}
+	if out.Message != "Workbench was succesfully created with image Jupyter | Minimal | CPU | Python 3.12:2025.1!" {
+		t.Errorf("unexpected message: %q", out.Message)
+	}
+
+	notebook, err := client.Resource(workbenchesGVR).Namespace("ns1").Get(ctx, "wb-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected notebook to be created: %v", err)
+	}
+	if claims := workbenchClaims(*notebook); len(claims) != 2 || claims[0] != "wb-1" || claims[1] != "data.v1" {
+		t.Errorf("expected wb-1 and data.v1 volumes, got: %v", claims)
+	}
+	volumes, _, _ := unstructured.NestedSlice(notebook.Object, "spec", "template", "spec", "volumes")
+	if name := volumes[2].(map[string]interface{})["name"]; name != "storage-1" {
+		t.Errorf("expected data.v1 to be mounted as volume storage-1, got: %v", name)
+	}
+	if _, err := client.Resource(pvcGVR).Namespace("ns1").Get(ctx, "wb-1", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected home PVC to be created: %v", err)
+	}
+	if annotation := notebook.GetAnnotations()["opendatahub.io/connections"]; annotation != "ns1/models" {
+		t.Errorf("expected connections annotation ns1/models, got: %q", annotation)
+	}
+	containers, _, _ := unstructured.NestedSlice(notebook.Object, "spec", "template", "spec", "containers")
+	container := containers[0].(map[string]interface{})
+	envFrom, _, _ := unstructured.NestedSlice(container, "envFrom")
+	if len(envFrom) != 1 {
+		t.Errorf("expected one envFrom entry, got: %v", envFrom)
+	}
+	env, _, _ := unstructured.NestedSlice(container, "env")
+	if len(env) != 3 {
+		t.Errorf("expected NOTEBOOK_ARGS, JUPYTER_IMAGE and EPOCHS env vars, got: %v", env)
+	}
+
+	input.WorkbenchName = "wb-2"
+	input.Connections = []string{"missing"}
+	if _, _, err := CreateWorkbench(ctx, nil, input); err == nil {
+		t.Errorf("expected error for missing connection")
+	}
+	input.Connections = nil
+	input.EnvVars = []EnvVar{{Name: "NOTEBOOK_ARGS", Value: "--debug"}}
+	if _, _, err := CreateWorkbench(ctx, nil, input); err == nil {
+		t.Errorf("expected error when overriding NOTEBOOK_ARGS")
+	}
+	input.EnvVars = nil
+	input.AdditionalStorage = []StorageMount{{StorageName: "data.v1"}, {StorageName: "data.v1", MountPath: "/data"}}
+	if _, _, err := CreateWorkbench(ctx, nil, input); err == nil {
+		t.Errorf("expected error when the same storage is listed twice")
+	}
+	input.WorkbenchName = "wb-3"
+	for _, storage := range [][]StorageMount{
+		{{StorageName: "data.v1", MountPath: "/opt/app-root/src"}},
+		{{StorageName: "data.v1", MountPath: "/mnt/data"}, {StorageName: "other", MountPath: "/mnt/data/"}},
+	} {
+		input.AdditionalStorage = storage
+		if _, _, err := CreateWorkbench(ctx, nil, input); err == nil {
+			t.Errorf("expected error for clashing mount paths %v", storage)
+		}
+	}
+	if _, err := client.Resource(pvcGVR).Namespace("ns1").Get(ctx, "wb-3", metav1.GetOptions{}); err == nil {
+		t.Errorf("expected no home PVC to be created when mount paths clash")
+	}
+}
+
+func TestUpdateWorkbenchEnvironment(t *testing.T) {
+	orig := getDynamicClient
+	defer func() { getDynamicClient = orig }()
+
+	workbench := newUnstructuredWorkbench("wb-1", "ns1")
+	workbench.SetAnnotations(map[string]string{"opendatahub.io/connections": "ns1/old"})
+	_ = unstructured.SetNestedSlice(workbench.Object, []interface{}{
+		map[string]interface{}{
+			"name": "wb-1",
+			"env": []interface{}{
+				map[string]interface{}{"name": "JUPYTER_IMAGE", "value": "image"},
+				map[string]interface{}{"name": "EPOCHS", "value": "3"},
+			},
+			"envFrom": []interface{}{connectionEnvFrom("old")},
+		},
+	}, "spec", "template", "spec", "containers")
+
+	scheme := runtime.NewScheme()
+	client := dynamicfake.NewSimpleDynamicClient(scheme,
+		workbench,
+		newUnstructuredConnection("models", "ns1", "s3", nil),
+	)
+	getDynamicClient = func() (dynamic.Interface, error) {
+		return client, nil
+	}
+	ctx := context.Background()
+
+	_, out, err := UpdateWorkbenchEnvironment(ctx, nil, UpdateWorkbenchEnvironmentInput{
+		Namespace:         "ns1",
+		WorkbenchName:     "wb-1",
+		AddConnections:    []string{"models"},
+		RemoveConnections: []string{"old"},
+		SecretEnvVars:     []SecretEnvVar{{Name: "HF_TOKEN", SecretName: "hf", SecretKey: "token"}},
+		RemoveEnvVars:     []string{"EPOCHS"},
+	})
+	if err != nil {
+		t.Fatalf("UpdateWorkbenchEnvironment returned error: %v", err)
+	}
+	if out.Message != "Environment of workbench wb-1 was updated" {
+		t.Errorf("unexpected message: %q", out.Message)
+	}
+
+	updated, _ := client.Resource(workbenchesGVR).Namespace("ns1").Get(ctx, "wb-1", metav1.GetOptions{})
+	if annotation := updated.GetAnnotations()["opendatahub.io/connections"]; annotation != "ns1/models" {
+		t.Errorf("expected connections annotation ns1/models, got: %q", annotation)
+	}
+	containers, _, _ := unstructured.NestedSlice(updated.Object, "spec", "template", "spec", "containers")
+	container := containers[0].(map[string]interface{})
+	envFrom, _, _ := unstructured.NestedSlice(container, "envFrom")
+	if len(envFrom) != 1 {
+		t.Fatalf("expected one envFrom entry, got: %v", envFrom)
+	}
+	if name, _, _ := unstructured.NestedString(envFrom[0].(map[string]interface{}), "secretRef", "name"); name != "models" {
+		t.Errorf("expected models connection in envFrom, got: %q", name)
+	}
+	env, _, _ := unstructured.NestedSlice(container, "env")
+	if len(env) != 2 || env[0].(map[string]interface{})["name"] != "JUPYTER_IMAGE" || env[1].(map[string]interface{})["name"] != "HF_TOKEN" {
+		t.Errorf("expected JUPYTER_IMAGE and HF_TOKEN env vars, got: %v", env)
+	}
+
+	if _, _, err := UpdateWorkbenchEnvironment(ctx, nil, UpdateWorkbenchEnvironmentInput{Namespace: "ns1", WorkbenchName: "wb-1", RemoveEnvVars: []string{"JUPYTER_IMAGE"}}); err == nil {
+		t.Errorf("expected error when removing JUPYTER_IMAGE")
+	}
+}
+
+// setImageStreamTagAnnotations adds annotations to a tag created by newUnstructuredImageStream
+func setImageStreamTagAnnotations(u *unstructured.Unstructured, tagName string, annotations map[string]string) {
+	tags, _, _ := unstructured.NestedSlice(u.Object, "spec", "tags")
+	for _, t := range tags {
+		tag := t.(map[string]interface{})
+		if tag["name"] != tagName {
+			continue
+		}
+		tagAnnotations := tag["annotations"].(map[string]interface{})
+		for key, value := range annotations {
+			tagAnnotations[key] = value
+		}
+	}
+	_ = unstructured.SetNestedSlice(u.Object, tags, "spec", "tags")
+}
+
+func newPyTorchImageStream() *unstructured.Unstructured {
+	image := newUnstructuredImageStream("pytorch", "PyTorch", "2024.1", "2024.2")
+	image.SetAnnotations(map[string]string{
+		"opendatahub.io/notebook-image-name": "PyTorch",
+		"opendatahub.io/notebook-image-desc": "Jupyter notebook image with PyTorch libraries",
+	})
+	setImageStreamTagAnnotations(image, "2024.1", map[string]string{
+		"opendatahub.io/notebook-software":            `[{"name":"CUDA","version":"12.1"},{"name":"Python","version":"v3.9"}]`,
+		"opendatahub.io/notebook-python-dependencies": `[{"name":"PyTorch","version":"2.2"}]`,
+		"opendatahub.io/image-tag-outdated":           "true",
+	})
+	setImageStreamTagAnnotations(image, "2024.2", map[string]string{
+		"opendatahub.io/notebook-software":            `[{"name":"CUDA","version":"12.1"},{"name":"Python","version":"v3.11"}]`,
+		"opendatahub.io/notebook-python-dependencies": `[{"name":"PyTorch","version":"2.4"},{"name":"Tensorboard","version":"2.16"}]`,
+		"opendatahub.io/workbench-image-recommended":  "true",
+	})
+	return image
 }
 
-// TODO
 func TestListImages(t *testing.T) {
+	orig := getDynamicClient
+	defer func() { getDynamicClient = orig }()
+
+	scheme := runtime.NewScheme()
+	client := dynamicfake.NewSimpleDynamicClient(scheme, newPyTorchImageStream())
+	getDynamicClient = func() (dynamic.Interface, error) {
+		return client, nil
+	}
+	ctx := context.Background()
+
+	images, err := GetImages(ctx)
+	if err != nil {
+		t.Fatalf("GetImages returned error: %v", err)
+	}
+	if len(images) != 1 || len(images[0].Tags) != 2 {
+		t.Fatalf("expected one image with two tags, got: %+v", images)
+	}
+	tag := images[0].Tags[1]
+	if tag.PythonVersion != "3.11" || !tag.Recommended || tag.Outdated || tag.BuildCommit != "abc123" {
+		t.Errorf("unexpected tag metadata: %+v", tag)
+	}
+	if len(tag.Packages) != 2 || tag.Packages[0] != (ImagePackage{Name: "PyTorch", Version: "2.4"}) {
+		t.Errorf("unexpected packages: %+v", tag.Packages)
+	}
+	if !images[0].Tags[0].Outdated || images[0].Description != "Jupyter notebook image with PyTorch libraries" {
+		t.Errorf("expected outdated first tag and description, got: %+v", images[0])
+	}
+
+	_, out, err := ListImages(ctx, nil, ListWorkbenchesInput{})
+	if err != nil {
+		t.Fatalf("ListImages returned error: %v", err)
+	}
+	expected := "Image: PyTorch\n" +
+		" URL: image-registry/redhat-ods-applications/pytorch\n" +
+		" Description: Jupyter notebook image with PyTorch libraries\n" +
+		" Versions:\n" +
+		" - 2024.1 (outdated): Python 3.9, CUDA 12.1, PyTorch 2.2\n" +
+		" - 2024.2 (recommended): Python 3.11, CUDA 12.1, PyTorch 2.4, Tensorboard 2.16\n"
+	if out.Images != expected {
+		t.Errorf("unexpected images:\n%s", out.Images)
+	}
+}
+
+func TestGetImageInfoDefaultTag(t *testing.T) {
+	orig := getDynamicClient
+	defer func() { getDynamicClient = orig }()
+
+	scheme := runtime.NewScheme()
+	client := dynamicfake.NewSimpleDynamicClient(scheme, newPyTorchImageStream())
+	getDynamicClient = func() (dynamic.Interface, error) {
+		return client, nil
+	}
+	ctx := context.Background()
+
+	repoURL, tag, imageName, err := GetImageInfo(ctx, "PyTorch", "")
+	if err != nil {
+		t.Fatalf("GetImageInfo returned error: %v", err)
+	}
+	if tag.Name != "2024.2" || imageName != "pytorch" || repoURL != "image-registry/redhat-ods-applications/pytorch" {
+		t.Errorf("expected recommended tag 2024.2 of pytorch, got %s of %s at %s", tag.Name, imageName, repoURL)
+	}
+	if _, _, _, err := GetImageInfo(ctx, "PyTorch", "2023.1"); err == nil {
+		t.Errorf("expected error for missing tag")
+	}
+}
+
+func TestDefaultImageTag(t *testing.T) {
+	for _, c := range []struct {
+		tags     []ImageTagDef
+		expected string
+	}{
+		{[]ImageTagDef{{Name: "2025.1"}, {Name: "2024.2", Recommended: true}}, "2024.2"},
+		{[]ImageTagDef{{Name: "2024.1"}, {Name: "2025.1", Outdated: true}, {Name: "2024.2"}}, "2024.2"},
+		{[]ImageTagDef{{Name: "2024.1", Outdated: true}, {Name: "2024.2", Outdated: true}}, "2024.2"},
+	} {
+		tag, ok := defaultImageTag(ImageDef{Tags: c.tags})
+		if !ok || tag.Name != c.expected {
+			t.Errorf("expected tag %s for %+v, got %s", c.expected, c.tags, tag.Name)
+		}
+	}
+	if _, ok := defaultImageTag(ImageDef{}); ok {
+		t.Errorf("expected no tag for an image without tags")
+	}
 }
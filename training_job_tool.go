@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// container name the training operator expects in PyTorchJob replicas
+const pytorchContainerName = "pytorch"
+
+// Submits a Kubeflow Training Operator PyTorchJob with a master and the given number of workers
+func CreateTrainingJob(ctx context.Context, req *mcp.CallToolRequest, input CreateTrainingJobInput) (*mcp.CallToolResult, TrainingJobOutput, error) {
+	if len(input.Command) == 0 {
+		return nil, TrainingJobOutput{}, fmt.Errorf("command is required")
+	}
+	if input.Workers < 0 || input.GPUs < 0 {
+		return nil, TrainingJobOutput{}, fmt.Errorf("workers and GPUs can not be negative")
+	}
+
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, TrainingJobOutput{}, err
+	}
+
+	if input.StorageName != "" {
+		if _, err := dyn.Resource(pvcGVR).Namespace(input.Namespace).Get(ctx, input.StorageName, metav1.GetOptions{}); err != nil {
+			return nil, TrainingJobOutput{}, fmt.Errorf("failed to get storage %s: %v", input.StorageName, err)
+		}
+	}
+	if input.ConnectionName != "" {
+		if _, err := getConnection(ctx, dyn, input.Namespace, input.ConnectionName); err != nil {
+			return nil, TrainingJobOutput{}, err
+		}
+	}
+	if input.LocalQueue != "" {
+		if _, err := dyn.Resource(localQueuesGVR).Namespace(input.Namespace).Get(ctx, input.LocalQueue, metav1.GetOptions{}); err != nil {
+			return nil, TrainingJobOutput{}, fmt.Errorf("failed to get local queue %s: %v", input.LocalQueue, err)
+		}
+	}
+
+	_, err = dyn.Resource(pytorchJobsGVR).Namespace(input.Namespace).Create(ctx, newPyTorchJob(input), metav1.CreateOptions{})
+	if err != nil {
+		return nil, TrainingJobOutput{}, fmt.Errorf("failed to create training job: %v", err)
+	}
+
+	return nil, TrainingJobOutput{Message: fmt.Sprintf("Training job %s with 1 master and %d workers was succesfully created!", input.Name, input.Workers)}, nil
+}
+
+// Reports the state of a training job and of its master and worker replicas
+func GetTrainingJob(ctx context.Context, req *mcp.CallToolRequest, input TrainingJobInput) (*mcp.CallToolResult, TrainingJobOutput, error) {
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, TrainingJobOutput{}, err
+	}
+
+	job, err := dyn.Resource(pytorchJobsGVR).Namespace(input.Namespace).Get(ctx, input.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, TrainingJobOutput{}, fmt.Errorf("failed to get training job %s: %v", input.Name, err)
+	}
+
+	msg := fmt.Sprintf("Training job %s is %s\n", input.Name, trainingJobState(*job))
+	for _, replicaType := range []string{"Master", "Worker"} {
+		replicas, found, _ := unstructured.NestedInt64(job.Object, "spec", "pytorchReplicaSpecs", replicaType, "replicas")
+		if !found {
+			continue
+		}
+		active, _, _ := unstructured.NestedInt64(job.Object, "status", "replicaStatuses", replicaType, "active")
+		succeeded, _, _ := unstructured.NestedInt64(job.Object, "status", "replicaStatuses", replicaType, "succeeded")
+		failed, _, _ := unstructured.NestedInt64(job.Object, "status", "replicaStatuses", replicaType, "failed")
+		msg += fmt.Sprintf("- %s: %d replicas, %d active, %d succeeded, %d failed\n", replicaType, replicas, active, succeeded, failed)
+	}
+	return nil, TrainingJobOutput{Message: msg}, nil
+}
+
+// Returns the logs of the replica with the given rank, the master has rank 0
+func GetTrainingJobLogs(ctx context.Context, req *mcp.CallToolRequest, input TrainingJobLogsInput) (*mcp.CallToolResult, TrainingJobOutput, error) {
+	if input.Rank < 0 {
+		return nil, TrainingJobOutput{}, fmt.Errorf("rank can not be negative")
+	}
+	tailLines := input.TailLines
+	if tailLines == 0 {
+		tailLines = 100
+	}
+
+	clientset, err := getClientSet()
+	if err != nil {
+		return nil, TrainingJobOutput{}, err
+	}
+
+	replicaType, replicaIndex := "master", 0
+	if input.Rank > 0 {
+		replicaType, replicaIndex = "worker", input.Rank-1
+	}
+	selector := fmt.Sprintf("training.kubeflow.org/job-name=%s,training.kubeflow.org/replica-type=%s,training.kubeflow.org/replica-index=%d", input.Name, replicaType, replicaIndex)
+	pods, err := clientset.CoreV1().Pods(input.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, TrainingJobOutput{}, fmt.Errorf("failed to list pods: %v", err)
+	}
+	if len(pods.Items) == 0 {
+		return nil, TrainingJobOutput{}, fmt.Errorf("no pod found for rank %d of training job %s", input.Rank, input.Name)
+	}
+
+	pod := pods.Items[0]
+	logs, err := clientset.CoreV1().Pods(input.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container: pytorchContainerName,
+		TailLines: &tailLines,
+	}).DoRaw(ctx)
+	if err != nil {
+		return nil, TrainingJobOutput{}, fmt.Errorf("failed to get logs of pod %s: %v", pod.Name, err)
+	}
+
+	return nil, TrainingJobOutput{Message: fmt.Sprintf("Logs of pod %s (%s):\n%s", pod.Name, pod.Status.Phase, string(logs))}, nil
+}
+
+// Deletes a training job, the training operator removes its replica pods
+func DeleteTrainingJob(ctx context.Context, req *mcp.CallToolRequest, input TrainingJobInput) (*mcp.CallToolResult, TrainingJobOutput, error) {
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, TrainingJobOutput{}, err
+	}
+
+	err = dyn.Resource(pytorchJobsGVR).Namespace(input.Namespace).Delete(ctx, input.Name, metav1.DeleteOptions{})
+	if err != nil {
+		return nil, TrainingJobOutput{}, fmt.Errorf("failed to delete training job %s: %v", input.Name, err)
+	}
+
+	return nil, TrainingJobOutput{Message: fmt.Sprintf("Training job %s was deleted", input.Name)}, nil
+}
+
+func newPyTorchJob(input CreateTrainingJobInput) *unstructured.Unstructured {
+	container := map[string]interface{}{
+		"name":      pytorchContainerName,
+		"image":     input.Image,
+		"command":   stringsToInterfaces(input.Command),
+		"resources": containerResources(input.CPU, "1", input.Memory, "4Gi", input.GPUs),
+	}
+	podSpec := map[string]interface{}{
+		"containers": []interface{}{container},
+	}
+	if input.StorageName != "" {
+		mountPath := input.MountPath
+		if mountPath == "" {
+			mountPath = "/mnt/storage"
+		}
+		container["volumeMounts"] = []interface{}{storageVolumeMount("storage", input.StorageName, mountPath)}
+		podSpec["volumes"] = []interface{}{storageVolume("storage", input.StorageName)}
+	}
+	if input.ConnectionName != "" {
+		container["envFrom"] = []interface{}{connectionEnvFrom(input.ConnectionName)}
+	}
+
+	replicaSpec := func(replicas int) map[string]interface{} {
+		return map[string]interface{}{
+			"replicas":      int64(replicas),
+			"restartPolicy": "OnFailure",
+			"template": map[string]interface{}{
+				"spec": runtime.DeepCopyJSON(podSpec),
+			},
+		}
+	}
+	replicaSpecs := map[string]interface{}{
+		"Master": replicaSpec(1),
+	}
+	if input.Workers > 0 {
+		replicaSpecs["Worker"] = replicaSpec(input.Workers)
+	}
+
+	labels := map[string]interface{}{
+		"opendatahub.io/dashboard": "true",
+	}
+	if input.LocalQueue != "" {
+		labels["kueue.x-k8s.io/queue-name"] = input.LocalQueue
+	}
+
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "kubeflow.org/v1",
+			"kind":       "PyTorchJob",
+			"metadata": map[string]interface{}{
+				"name":      input.Name,
+				"namespace": input.Namespace,
+				"labels":    labels,
+			},
+			"spec": map[string]interface{}{
+				"pytorchReplicaSpecs": replicaSpecs,
+			},
+		},
+	}
+}
+
+// trainingJobState returns the type of the newest true condition, f.e. Running or Failed with its message
+func trainingJobState(job unstructured.Unstructured) string {
+	conditions, _, _ := unstructured.NestedSlice(job.Object, "status", "conditions")
+	for i := len(conditions) - 1; i >= 0; i-- {
+		condition, ok := conditions[i].(map[string]interface{})
+		if !ok || condition["status"] != "True" {
+			continue
+		}
+		state, _ := condition["type"].(string)
+		if message, _ := condition["message"].(string); message != "" && (state == "Failed" || state == "Suspended") {
+			return fmt.Sprintf("%s (%s)", state, message)
+		}
+		return state
+	}
+	return "Pending"
+}
+
+func stringsToInterfaces(values []string) []interface{} {
+	result := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		result = append(result, value)
+	}
+	return result
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTrainingJobPod(job, replicaType, replicaIndex string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      job + "-" + replicaType + "-" + replicaIndex,
+			Namespace: "ns1",
+			Labels: map[string]string{
+				"training.kubeflow.org/job-name":      job,
+				"training.kubeflow.org/replica-type":  replicaType,
+				"training.kubeflow.org/replica-index": replicaIndex,
+			},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func TestTrainingJobs(t *testing.T) {
+	origDynamic := getDynamicClient
+	origClientSet := getClientSet
+	defer func() {
+		getDynamicClient = origDynamic
+		getClientSet = origClientSet
+	}()
+
+	scheme := runtime.NewScheme()
+	client := dynamicfake.NewSimpleDynamicClient(scheme,
+		newUnstructuredPVC("training-data", "ns1", "20Gi", "gp3-csi"),
+		newUnstructuredConnection("datasets", "ns1", "s3", map[string]string{"AWS_S3_BUCKET": "datasets"}),
+	)
+	getDynamicClient = func() (dynamic.Interface, error) {
+		return client, nil
+	}
+	clientset := fake.NewSimpleClientset(
+		newTrainingJobPod("fine-tune", "master", "0"),
+		newTrainingJobPod("fine-tune", "worker", "0"),
+		newTrainingJobPod("fine-tune", "worker", "1"),
+	)
+	getClientSet = func() (kubernetes.Interface, error) {
+		return clientset, nil
+	}
+	ctx := context.Background()
+
+	input := CreateTrainingJobInput{
+		Namespace:      "ns1",
+		Name:           "fine-tune",
+		Image:          "quay.io/modh/training:py311-cuda121-torch241",
+		Command:        []string{"torchrun", "/mnt/storage/train.py"},
+		Workers:        2,
+		GPUs:           1,
+		StorageName:    "training-data",
+		ConnectionName: "datasets",
+	}
+	if _, _, err := CreateTrainingJob(ctx, nil, CreateTrainingJobInput{Namespace: "ns1", Name: "no-command", Image: input.Image}); err == nil {
+		t.Errorf("expected error without command")
+	}
+	missingStorage := input
+	missingStorage.StorageName = "missing"
+	if _, _, err := CreateTrainingJob(ctx, nil, missingStorage); err == nil {
+		t.Errorf("expected error for missing storage")
+	}
+
+	_, out, err := CreateTrainingJob(ctx, nil, input)
+	if err != nil {
+		t.Fatalf("CreateTrainingJob returned error: %v", err)
+	}
+	if out.Message != "Training job fine-tune with 1 master and 2 workers was succesfully created!" {
+		t.Errorf("unexpected message: %q", out.Message)
+	}
+
+	job, err := client.Resource(pytorchJobsGVR).Namespace("ns1").Get(ctx, "fine-tune", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected training job to be created: %v", err)
+	}
+	workers, _, _ := unstructured.NestedInt64(job.Object, "spec", "pytorchReplicaSpecs", "Worker", "replicas")
+	containers, _, _ := unstructured.NestedSlice(job.Object, "spec", "pytorchReplicaSpecs", "Master", "template", "spec", "containers")
+	container := containers[0].(map[string]interface{})
+	mounts, _ := container["volumeMounts"].([]interface{})
+	limits, _, _ := unstructured.NestedStringMap(container, "resources", "limits")
+	if workers != 2 || len(mounts) != 1 || limits["nvidia.com/gpu"] != "1" || container["name"] != "pytorch" {
+		t.Errorf("unexpected training job spec: workers %d, container %v", workers, container)
+	}
+
+	// pretend the training operator started the replicas
+	_ = unstructured.SetNestedSlice(job.Object, []interface{}{
+		map[string]interface{}{"type": "Created", "status": "True"},
+		map[string]interface{}{"type": "Running", "status": "True"},
+	}, "status", "conditions")
+	_ = unstructured.SetNestedField(job.Object, int64(1), "status", "replicaStatuses", "Master", "active")
+	_ = unstructured.SetNestedField(job.Object, int64(1), "status", "replicaStatuses", "Worker", "active")
+	_ = unstructured.SetNestedField(job.Object, int64(1), "status", "replicaStatuses", "Worker", "failed")
+	if _, err := client.Resource(pytorchJobsGVR).Namespace("ns1").Update(ctx, job, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("failed to update training job status: %v", err)
+	}
+
+	_, out, err = GetTrainingJob(ctx, nil, TrainingJobInput{Namespace: "ns1", Name: "fine-tune"})
+	if err != nil {
+		t.Fatalf("GetTrainingJob returned error: %v", err)
+	}
+	expected := "Training job fine-tune is Running\n" +
+		"- Master: 1 replicas, 1 active, 0 succeeded, 0 failed\n" +
+		"- Worker: 2 replicas, 1 active, 0 succeeded, 1 failed\n"
+	if out.Message != expected {
+		t.Errorf("unexpected state:\n%s", out.Message)
+	}
+
+	_, out, err = GetTrainingJobLogs(ctx, nil, TrainingJobLogsInput{Namespace: "ns1", Name: "fine-tune", Rank: 2})
+	if err != nil {
+		t.Fatalf("GetTrainingJobLogs returned error: %v", err)
+	}
+	if !strings.HasPrefix(out.Message, "Logs of pod fine-tune-worker-1 (Running):\n") {
+		t.Errorf("expected logs of the second worker, got: %q", out.Message)
+	}
+	if _, _, err := GetTrainingJobLogs(ctx, nil, TrainingJobLogsInput{Namespace: "ns1", Name: "fine-tune", Rank: 3}); err == nil {
+		t.Errorf("expected error for a rank without pod")
+	}
+
+	if _, _, err := DeleteTrainingJob(ctx, nil, TrainingJobInput{Namespace: "ns1", Name: "fine-tune"}); err != nil {
+		t.Fatalf("DeleteTrainingJob returned error: %v", err)
+	}
+	if _, err := client.Resource(pytorchJobsGVR).Namespace("ns1").Get(ctx, "fine-tune", metav1.GetOptions{}); err == nil {
+		t.Errorf("expected training job to be deleted")
+	}
+}
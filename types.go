@@ -4,8 +4,46 @@ import "k8s.io/apimachinery/pkg/runtime/schema"
 
 var workbenchesGVR = schema.GroupVersionResource{Group: "kubeflow.org", Version: "v1", Resource: "notebooks"}
 
+var imageStreamsGVR = schema.GroupVersionResource{Group: "image.openshift.io", Version: "v1", Resource: "imagestreams"}
+
 var pvcGVR = schema.GroupVersionResource{Group: "", Version: "v1", Resource: "persistentvolumeclaims"}
 
+var secretsGVR = schema.GroupVersionResource{Group: "", Version: "v1", Resource: "secrets"}
+
+var storageClassesGVR = schema.GroupVersionResource{Group: "storage.k8s.io", Version: "v1", Resource: "storageclasses"}
+
+var inferenceServicesGVR = schema.GroupVersionResource{Group: "serving.kserve.io", Version: "v1beta1", Resource: "inferenceservices"}
+
+var servingRuntimesGVR = schema.GroupVersionResource{Group: "serving.kserve.io", Version: "v1alpha1", Resource: "servingruntimes"}
+
+var templatesGVR = schema.GroupVersionResource{Group: "template.openshift.io", Version: "v1", Resource: "templates"}
+
+var serviceAccountsGVR = schema.GroupVersionResource{Group: "", Version: "v1", Resource: "serviceaccounts"}
+
+var rolesGVR = schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "roles"}
+
+var pipelineServersGVR = schema.GroupVersionResource{Group: "datasciencepipelinesapplications.opendatahub.io", Version: "v1", Resource: "datasciencepipelinesapplications"}
+
+var projectsGVR = schema.GroupVersionResource{Group: "project.openshift.io", Version: "v1", Resource: "projects"}
+
+var projectRequestsGVR = schema.GroupVersionResource{Group: "project.openshift.io", Version: "v1", Resource: "projectrequests"}
+
+var namespacesGVR = schema.GroupVersionResource{Group: "", Version: "v1", Resource: "namespaces"}
+
+var roleBindingsGVR = schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "rolebindings"}
+
+var localQueuesGVR = schema.GroupVersionResource{Group: "kueue.x-k8s.io", Version: "v1beta1", Resource: "localqueues"}
+
+var clusterQueuesGVR = schema.GroupVersionResource{Group: "kueue.x-k8s.io", Version: "v1beta1", Resource: "clusterqueues"}
+
+var workloadsGVR = schema.GroupVersionResource{Group: "kueue.x-k8s.io", Version: "v1beta1", Resource: "workloads"}
+
+var rayClustersGVR = schema.GroupVersionResource{Group: "ray.io", Version: "v1", Resource: "rayclusters"}
+
+var pytorchJobsGVR = schema.GroupVersionResource{Group: "kubeflow.org", Version: "v1", Resource: "pytorchjobs"}
+
+var routesGVR = schema.GroupVersionResource{Group: "route.openshift.io", Version: "v1", Resource: "routes"}
+
 type PodsOutput struct {
 	Pods string `json:"pods" jsonschema_description:"the list of pods"`
 }
@@ -18,6 +56,10 @@ type ListWorkbenchesInput struct {
 	Namespace string `json:"namespace" jsonschema_description:"the namespace of the workbench"`
 }
 
+type ListAllWorkbenchesInput struct {
+	DashboardOnly bool `json:"dashboardOnly,omitempty" jsonschema_description:"only include namespaces labelled opendatahub.io/dashboard=true (data science projects)"`
+}
+
 type ChangeWorkbenchStatusInput struct {
 	Namespace     string          `json:"namespace" jsonschema_description:"the namespace of the workbench"`
 	WorkbenchName string          `json:"workbenchName" jsonschema_description:"the name of the workbench"`
@@ -48,12 +90,469 @@ func (s WorkbenchStatus) String() string {
 }
 
 type CreateWorkbenchInput struct {
-	Namespace        string `json:"namespace" jsonschema_description:"the namespace of the workbench"`
-	WorkbenchName    string `json:"workbenchName" jsonschema_description:"the name of the workbench"`
-	ImageDisplayName string `json:"imageDisplayName" jsonschema_description:"the image display name - f.e. Jupyter | Data Science | CPU | Python 3.12"`
-	ImageTag         string `json:"imageTag" jsonschema_description:"the image tag "`
+	Namespace         string         `json:"namespace" jsonschema_description:"the namespace of the workbench"`
+	WorkbenchName     string         `json:"workbenchName" jsonschema_description:"the name of the workbench"`
+	ImageDisplayName  string         `json:"imageDisplayName" jsonschema_description:"the image display name - f.e. Jupyter | Data Science | CPU | Python 3.12"`
+	ImageTag          string         `json:"imageTag,omitempty" jsonschema_description:"the image tag, the recommended tag when empty"`
+	StorageName       string         `json:"storageName,omitempty" jsonschema_description:"the name of an existing persistent volume claim to use as the home volume, a new one named after the workbench is created when empty"`
+	AdditionalStorage []StorageMount `json:"additionalStorage,omitempty" jsonschema_description:"existing persistent volume claims to mount into the workbench"`
+	Connections       []string       `json:"connections,omitempty" jsonschema_description:"names of connections in the namespace to inject into the workbench as environment variables"`
+	EnvVars           []EnvVar       `json:"envVars,omitempty" jsonschema_description:"additional environment variables of the workbench"`
+	SecretEnvVars     []SecretEnvVar `json:"secretEnvVars,omitempty" jsonschema_description:"additional environment variables of the workbench read from secrets"`
+}
+
+type EnvVar struct {
+	Name  string `json:"name" jsonschema_description:"the name of the environment variable"`
+	Value string `json:"value" jsonschema_description:"the value of the environment variable"`
+}
+
+type SecretEnvVar struct {
+	Name       string `json:"name" jsonschema_description:"the name of the environment variable"`
+	SecretName string `json:"secretName" jsonschema_description:"the name of the secret in the namespace of the workbench"`
+	SecretKey  string `json:"secretKey" jsonschema_description:"the key in the secret holding the value"`
+}
+
+type StorageMount struct {
+	StorageName string `json:"storageName" jsonschema_description:"the name of the persistent volume claim"`
+	MountPath   string `json:"mountPath,omitempty" jsonschema_description:"the path to mount the storage at, /opt/app-root/src/<storageName> when empty"`
 }
 
 type ListImagesOutput struct {
 	Images string `json:"images" jsonschema_description:"the list of images"`
 }
+
+type ListProjectsInput struct{}
+
+type ListProjectsOutput struct {
+	Projects string `json:"projects" jsonschema_description:"the list of data science projects"`
+}
+
+type CreateProjectInput struct {
+	Name        string `json:"name" jsonschema_description:"the name of the project namespace"`
+	DisplayName string `json:"displayName,omitempty" jsonschema_description:"the display name of the project shown in the dashboard"`
+	Description string `json:"description,omitempty" jsonschema_description:"the description of the project"`
+}
+
+type ProjectInput struct {
+	Name string `json:"name" jsonschema_description:"the name of the project namespace"`
+}
+
+type ProjectOutput struct {
+	Message string `json:"message" jsonschema_description:"the message with result of project change"`
+}
+
+type ListPermissionsOutput struct {
+	Permissions string `json:"permissions" jsonschema_description:"the list of users and groups with access to the project"`
+}
+
+type GrantPermissionInput struct {
+	Namespace   string `json:"namespace" jsonschema_description:"the namespace of the project"`
+	SubjectKind string `json:"subjectKind" jsonschema_description:"the kind of the subject - User or Group"`
+	SubjectName string `json:"subjectName" jsonschema_description:"the name of the user or group"`
+	Role        string `json:"role" jsonschema_description:"the role to grant - admin, edit or view"`
+}
+
+type RevokePermissionInput struct {
+	Namespace   string `json:"namespace" jsonschema_description:"the namespace of the project"`
+	SubjectKind string `json:"subjectKind" jsonschema_description:"the kind of the subject - User or Group"`
+	SubjectName string `json:"subjectName" jsonschema_description:"the name of the user or group"`
+	Role        string `json:"role,omitempty" jsonschema_description:"the role to revoke - admin, edit or view, all roles when empty"`
+}
+
+type PermissionOutput struct {
+	Message string `json:"message" jsonschema_description:"the message with result of permission change"`
+}
+
+type ListStorageOutput struct {
+	Storage string `json:"storage" jsonschema_description:"the list of persistent volume claims in the project"`
+}
+
+type CreateStorageInput struct {
+	Namespace    string `json:"namespace" jsonschema_description:"the namespace of the storage"`
+	StorageName  string `json:"storageName" jsonschema_description:"the name of the persistent volume claim"`
+	Size         string `json:"size" jsonschema_description:"the size of the storage - f.e. 20Gi"`
+	StorageClass string `json:"storageClass,omitempty" jsonschema_description:"the storage class, the cluster default when empty"`
+	DisplayName  string `json:"displayName,omitempty" jsonschema_description:"the display name of the storage shown in the dashboard"`
+	Description  string `json:"description,omitempty" jsonschema_description:"the description of the storage"`
+}
+
+type ExpandStorageInput struct {
+	Namespace   string `json:"namespace" jsonschema_description:"the namespace of the storage"`
+	StorageName string `json:"storageName" jsonschema_description:"the name of the persistent volume claim"`
+	Size        string `json:"size" jsonschema_description:"the new size of the storage, must be bigger than the current one - f.e. 50Gi"`
+}
+
+type StorageInput struct {
+	Namespace   string `json:"namespace" jsonschema_description:"the namespace of the storage"`
+	StorageName string `json:"storageName" jsonschema_description:"the name of the persistent volume claim"`
+}
+
+type StorageOutput struct {
+	Message string `json:"message" jsonschema_description:"the message with result of storage change"`
+}
+
+type AttachStorageInput struct {
+	Namespace     string `json:"namespace" jsonschema_description:"the namespace of the workbench"`
+	WorkbenchName string `json:"workbenchName" jsonschema_description:"the name of the workbench"`
+	StorageName   string `json:"storageName" jsonschema_description:"the name of the persistent volume claim"`
+	MountPath     string `json:"mountPath,omitempty" jsonschema_description:"the path to mount the storage at, /opt/app-root/src/<storageName> when empty"`
+}
+
+type DetachStorageInput struct {
+	Namespace     string `json:"namespace" jsonschema_description:"the namespace of the workbench"`
+	WorkbenchName string `json:"workbenchName" jsonschema_description:"the name of the workbench"`
+	StorageName   string `json:"storageName" jsonschema_description:"the name of the persistent volume claim"`
+}
+
+type ListConnectionsOutput struct {
+	Connections string `json:"connections" jsonschema_description:"the list of connections, secret values are never included"`
+}
+
+type CreateConnectionInput struct {
+	Namespace       string `json:"namespace" jsonschema_description:"the namespace of the connection"`
+	ConnectionName  string `json:"connectionName" jsonschema_description:"the name of the connection secret"`
+	ConnectionType  string `json:"connectionType" jsonschema_description:"the type of the connection - s3, uri or oci"`
+	DisplayName     string `json:"displayName,omitempty" jsonschema_description:"the display name of the connection shown in the dashboard"`
+	Description     string `json:"description,omitempty" jsonschema_description:"the description of the connection"`
+	AccessKeyID     string `json:"accessKeyId,omitempty" jsonschema_description:"s3 only - the access key id"`
+	SecretAccessKey string `json:"secretAccessKey,omitempty" jsonschema_description:"s3 only - the secret access key"`
+	Endpoint        string `json:"endpoint,omitempty" jsonschema_description:"s3 only - the endpoint URL - f.e. https://s3.amazonaws.com"`
+	Bucket          string `json:"bucket,omitempty" jsonschema_description:"s3 only - the bucket name"`
+	Region          string `json:"region,omitempty" jsonschema_description:"s3 only - the region - f.e. us-east-1"`
+	URI             string `json:"uri,omitempty" jsonschema_description:"uri only - the URI of the model"`
+	Registry        string `json:"registry,omitempty" jsonschema_description:"oci only - the registry host - f.e. quay.io"`
+	Username        string `json:"username,omitempty" jsonschema_description:"oci only - the registry username"`
+	Password        string `json:"password,omitempty" jsonschema_description:"oci only - the registry password or token"`
+}
+
+type ConnectionInput struct {
+	Namespace      string `json:"namespace" jsonschema_description:"the namespace of the connection"`
+	ConnectionName string `json:"connectionName" jsonschema_description:"the name of the connection secret"`
+}
+
+type ConnectionOutput struct {
+	Message string `json:"message" jsonschema_description:"the message with result of connection change"`
+}
+
+type UpdateWorkbenchEnvironmentInput struct {
+	Namespace         string         `json:"namespace" jsonschema_description:"the namespace of the workbench"`
+	WorkbenchName     string         `json:"workbenchName" jsonschema_description:"the name of the workbench"`
+	AddConnections    []string       `json:"addConnections,omitempty" jsonschema_description:"names of connections to inject into the workbench"`
+	RemoveConnections []string       `json:"removeConnections,omitempty" jsonschema_description:"names of connections to remove from the workbench"`
+	EnvVars           []EnvVar       `json:"envVars,omitempty" jsonschema_description:"environment variables to add or overwrite"`
+	SecretEnvVars     []SecretEnvVar `json:"secretEnvVars,omitempty" jsonschema_description:"environment variables read from secrets to add or overwrite"`
+	RemoveEnvVars     []string       `json:"removeEnvVars,omitempty" jsonschema_description:"names of environment variables to remove"`
+}
+
+type TestConnectionOutput struct {
+	Success bool     `json:"success" jsonschema_description:"whether the connection works"`
+	Reason  string   `json:"reason" jsonschema_description:"the reason of the result - Ok, InvalidConnection, EndpointUnreachable, InvalidCredentials, AccessDenied, BucketNotFound or Unknown"`
+	Message string   `json:"message" jsonschema_description:"the message with details of the result"`
+	Objects []string `json:"objects,omitempty" jsonschema_description:"a sample of object keys in the bucket"`
+}
+
+type ListModelDeploymentsInput struct {
+	Namespace string `json:"namespace,omitempty" jsonschema_description:"the namespace of the model deployments, all namespaces when empty"`
+}
+
+type ListModelDeploymentsOutput struct {
+	Deployments string `json:"deployments" jsonschema_description:"the list of model deployments"`
+}
+
+type DeployModelInput struct {
+	Namespace          string `json:"namespace" jsonschema_description:"the namespace to deploy the model in"`
+	ModelName          string `json:"modelName" jsonschema_description:"the name of the model deployment"`
+	DisplayName        string `json:"displayName,omitempty" jsonschema_description:"the display name of the model deployment shown in the dashboard"`
+	RuntimeTemplate    string `json:"runtimeTemplate" jsonschema_description:"the name or display name of the serving runtime template - f.e. vLLM NVIDIA GPU ServingRuntime for KServe"`
+	ModelFormat        string `json:"modelFormat,omitempty" jsonschema_description:"the model format, the first format supported by the runtime when empty"`
+	ConnectionName     string `json:"connectionName,omitempty" jsonschema_description:"the name of the connection the model is loaded from"`
+	ModelPath          string `json:"modelPath,omitempty" jsonschema_description:"the path of the model inside the connection"`
+	StorageURI         string `json:"storageUri,omitempty" jsonschema_description:"the URI of the model when no connection is used - f.e. oci://quay.io/org/model:1.0"`
+	Replicas           int    `json:"replicas,omitempty" jsonschema_description:"the number of model server replicas, 1 when empty"`
+	CPU                string `json:"cpu,omitempty" jsonschema_description:"the CPU requested and limited for each replica - f.e. 4"`
+	Memory             string `json:"memory,omitempty" jsonschema_description:"the memory requested and limited for each replica - f.e. 16Gi"`
+	Accelerator        string `json:"accelerator,omitempty" jsonschema_description:"the accelerator resource name - f.e. nvidia.com/gpu"`
+	AcceleratorCount   int    `json:"acceleratorCount,omitempty" jsonschema_description:"the number of accelerators for each replica, 1 when an accelerator is set"`
+	AcceleratorProfile string `json:"acceleratorProfile,omitempty" jsonschema_description:"the name of the accelerator profile recorded on the serving runtime, optional"`
+	ExternalRoute      bool   `json:"externalRoute,omitempty" jsonschema_description:"whether to expose the model through an external route"`
+	TokenAuth          bool   `json:"tokenAuth,omitempty" jsonschema_description:"whether to require a service account token to query the model"`
+}
+
+type ModelDeploymentOutput struct {
+	Message string `json:"message" jsonschema_description:"the message with result of model deployment change"`
+}
+
+type ListServingRuntimesInput struct{}
+
+type ListServingRuntimesOutput struct {
+	Runtimes string `json:"runtimes" jsonschema_description:"the list of serving runtime templates"`
+}
+
+type RunInferenceInput struct {
+	Namespace      string `json:"namespace" jsonschema_description:"the namespace of the model deployment"`
+	ModelName      string `json:"modelName" jsonschema_description:"the name of the model deployment"`
+	Protocol       string `json:"protocol,omitempty" jsonschema_description:"the inference protocol - openai-chat, openai-completions or kserve-v2, openai-chat when empty"`
+	Prompt         string `json:"prompt,omitempty" jsonschema_description:"the prompt sent to openai compatible endpoints"`
+	MaxTokens      int    `json:"maxTokens,omitempty" jsonschema_description:"the maximum number of generated tokens for openai compatible endpoints"`
+	Inputs         string `json:"inputs,omitempty" jsonschema_description:"kserve-v2 only - the JSON array of input tensors - f.e. [{\"name\":\"input\",\"shape\":[1,4],\"datatype\":\"FP32\",\"data\":[1,2,3,4]}]"`
+	UseInternalURL bool   `json:"useInternalUrl,omitempty" jsonschema_description:"whether to call the cluster internal URL instead of the route"`
+	Token          string `json:"token,omitempty" jsonschema_description:"the bearer token, a service account token is requested when the deployment requires auth and this is empty"`
+}
+
+type RunInferenceOutput struct {
+	Response   string `json:"response" jsonschema_description:"the response of the model"`
+	StatusCode int    `json:"statusCode" jsonschema_description:"the HTTP status code of the inference request"`
+	LatencyMs  int64  `json:"latencyMs" jsonschema_description:"the latency of the inference request in milliseconds"`
+	URL        string `json:"url" jsonschema_description:"the URL the request was sent to"`
+}
+
+type ScaleModelDeploymentInput struct {
+	Namespace   string `json:"namespace" jsonschema_description:"the namespace of the model deployment"`
+	ModelName   string `json:"modelName" jsonschema_description:"the name of the model deployment"`
+	MinReplicas int    `json:"minReplicas" jsonschema_description:"the minimum number of model server replicas"`
+	MaxReplicas int    `json:"maxReplicas,omitempty" jsonschema_description:"the maximum number of model server replicas, same as minReplicas when empty"`
+}
+
+type ChangeModelDeploymentStatusInput struct {
+	Namespace string          `json:"namespace" jsonschema_description:"the namespace of the model deployment"`
+	ModelName string          `json:"modelName" jsonschema_description:"the name of the model deployment"`
+	Status    WorkbenchStatus `json:"status" jsonschema_description:"the status of the model deployment"`
+}
+
+type ModelDeploymentInput struct {
+	Namespace string `json:"namespace" jsonschema_description:"the namespace of the model deployment"`
+	ModelName string `json:"modelName" jsonschema_description:"the name of the model deployment"`
+}
+
+type ModelRegistryInput struct {
+	RegistryURL string `json:"registryUrl,omitempty" jsonschema_description:"the URL of the model registry REST API, MODEL_REGISTRY_URL when empty, only MODEL_REGISTRY_URL is called with credentials"`
+}
+
+type ListRegisteredModelsOutput struct {
+	Models string `json:"models" jsonschema_description:"the list of registered models"`
+}
+
+type GetRegisteredModelInput struct {
+	RegistryURL string `json:"registryUrl,omitempty" jsonschema_description:"the URL of the model registry REST API, MODEL_REGISTRY_URL when empty, only MODEL_REGISTRY_URL is called with credentials"`
+	ModelName   string `json:"modelName" jsonschema_description:"the name of the registered model"`
+}
+
+type GetRegisteredModelOutput struct {
+	Versions string `json:"versions" jsonschema_description:"the versions of the registered model with their artifacts"`
+}
+
+type RegisterModelVersionInput struct {
+	RegistryURL        string `json:"registryUrl,omitempty" jsonschema_description:"the URL of the model registry REST API, MODEL_REGISTRY_URL when empty, only MODEL_REGISTRY_URL is called with credentials"`
+	ModelName          string `json:"modelName" jsonschema_description:"the name of the registered model, it is created when it does not exist"`
+	VersionName        string `json:"versionName" jsonschema_description:"the name of the new model version - f.e. v1"`
+	Namespace          string `json:"namespace" jsonschema_description:"the namespace of the s3 connection"`
+	ConnectionName     string `json:"connectionName" jsonschema_description:"the name of the s3 connection the model is stored in"`
+	ModelPath          string `json:"modelPath" jsonschema_description:"the path of the model inside the bucket of the connection"`
+	ModelFormatName    string `json:"modelFormatName,omitempty" jsonschema_description:"the model format - f.e. vLLM or onnx"`
+	ModelFormatVersion string `json:"modelFormatVersion,omitempty" jsonschema_description:"the version of the model format"`
+	Author             string `json:"author,omitempty" jsonschema_description:"the author of the model version"`
+	Description        string `json:"description,omitempty" jsonschema_description:"the description of the model version"`
+}
+
+type ModelRegistryOutput struct {
+	Message string `json:"message" jsonschema_description:"the message with result of model registry change"`
+}
+
+type DeployRegisteredModelInput struct {
+	RegistryURL        string `json:"registryUrl,omitempty" jsonschema_description:"the URL of the model registry REST API, MODEL_REGISTRY_URL when empty, only MODEL_REGISTRY_URL is called with credentials"`
+	ModelName          string `json:"modelName" jsonschema_description:"the name of the registered model"`
+	VersionName        string `json:"versionName" jsonschema_description:"the name of the model version to deploy"`
+	Namespace          string `json:"namespace" jsonschema_description:"the namespace to deploy the model in"`
+	DeploymentName     string `json:"deploymentName" jsonschema_description:"the name of the model deployment"`
+	RuntimeTemplate    string `json:"runtimeTemplate" jsonschema_description:"the name or display name of the serving runtime template"`
+	ConnectionName     string `json:"connectionName,omitempty" jsonschema_description:"the connection to load s3 artifacts with, the connection recorded on the artifact when empty"`
+	Replicas           int    `json:"replicas,omitempty" jsonschema_description:"the number of model server replicas, 1 when empty"`
+	CPU                string `json:"cpu,omitempty" jsonschema_description:"the CPU requested and limited for each replica - f.e. 4"`
+	Memory             string `json:"memory,omitempty" jsonschema_description:"the memory requested and limited for each replica - f.e. 16Gi"`
+	Accelerator        string `json:"accelerator,omitempty" jsonschema_description:"the accelerator resource name - f.e. nvidia.com/gpu"`
+	AcceleratorCount   int    `json:"acceleratorCount,omitempty" jsonschema_description:"the number of accelerators for each replica, 1 when an accelerator is set"`
+	AcceleratorProfile string `json:"acceleratorProfile,omitempty" jsonschema_description:"the name of the accelerator profile recorded on the serving runtime, optional"`
+	ExternalRoute      bool   `json:"externalRoute,omitempty" jsonschema_description:"whether to expose the model through an external route"`
+	TokenAuth          bool   `json:"tokenAuth,omitempty" jsonschema_description:"whether to require a service account token to query the model"`
+}
+
+type CreatePipelineServerInput struct {
+	Namespace      string `json:"namespace" jsonschema_description:"the namespace of the pipeline server"`
+	ConnectionName string `json:"connectionName" jsonschema_description:"the name of the s3 connection used to store pipeline artifacts"`
+	Name           string `json:"name,omitempty" jsonschema_description:"the name of the pipeline server, dspa when empty"`
+}
+
+type PipelineServerOutput struct {
+	Message string `json:"message" jsonschema_description:"the message with the state of the pipeline server"`
+}
+
+type PipelineServerInput struct {
+	Namespace string `json:"namespace" jsonschema_description:"the namespace of the pipeline server"`
+	APIURL    string `json:"apiURL,omitempty" jsonschema_description:"the URL of the pipelines API, read from the pipeline server when empty, a given URL is called without credentials"`
+}
+
+type ListPipelinesOutput struct {
+	Pipelines string `json:"pipelines" jsonschema_description:"the list of pipelines with their versions"`
+}
+
+type StartPipelineRunInput struct {
+	Namespace  string                 `json:"namespace" jsonschema_description:"the namespace of the pipeline server"`
+	APIURL     string                 `json:"apiURL,omitempty" jsonschema_description:"the URL of the pipelines API, read from the pipeline server when empty, a given URL is called without credentials"`
+	Pipeline   string                 `json:"pipeline" jsonschema_description:"the name or ID of the pipeline to run"`
+	VersionID  string                 `json:"versionID,omitempty" jsonschema_description:"the ID of the pipeline version to run, the newest version when empty"`
+	RunName    string                 `json:"runName" jsonschema_description:"the name of the run"`
+	Parameters map[string]interface{} `json:"parameters,omitempty" jsonschema_description:"the input parameters of the pipeline"`
+}
+
+type ListPipelineRunsOutput struct {
+	Runs string `json:"runs" jsonschema_description:"the list of pipeline runs with their state"`
+}
+
+type PipelineRunInput struct {
+	Namespace string `json:"namespace" jsonschema_description:"the namespace of the pipeline server"`
+	APIURL    string `json:"apiURL,omitempty" jsonschema_description:"the URL of the pipelines API, read from the pipeline server when empty, a given URL is called without credentials"`
+	RunID     string `json:"runID" jsonschema_description:"the ID of the pipeline run"`
+}
+
+type PipelineRunOutput struct {
+	Message string `json:"message" jsonschema_description:"the message with the result of the operation"`
+}
+
+type UploadPipelineInput struct {
+	Namespace    string `json:"namespace" jsonschema_description:"the namespace of the pipeline server"`
+	APIURL       string `json:"apiURL,omitempty" jsonschema_description:"the URL of the pipelines API, read from the pipeline server when empty, a given URL is called without credentials"`
+	PipelineName string `json:"pipelineName" jsonschema_description:"the name of the pipeline, a new version is uploaded when the pipeline already exists"`
+	VersionName  string `json:"versionName,omitempty" jsonschema_description:"the name of the new pipeline version, generated when empty"`
+	Description  string `json:"description,omitempty" jsonschema_description:"the description of the pipeline or pipeline version"`
+	PipelineYAML string `json:"pipelineYAML,omitempty" jsonschema_description:"the compiled KFP IR YAML of the pipeline"`
+	Workbench    string `json:"workbench,omitempty" jsonschema_description:"the running workbench to read the YAML from instead of pipelineYAML"`
+	Path         string `json:"path,omitempty" jsonschema_description:"the path of the YAML in the workbench, relative to its home directory, has to be on the home directory or on mounted storage"`
+}
+
+type CreateRecurringRunInput struct {
+	Namespace       string                 `json:"namespace" jsonschema_description:"the namespace of the pipeline server"`
+	APIURL          string                 `json:"apiURL,omitempty" jsonschema_description:"the URL of the pipelines API, read from the pipeline server when empty, a given URL is called without credentials"`
+	Pipeline        string                 `json:"pipeline" jsonschema_description:"the name or ID of the pipeline to run"`
+	VersionID       string                 `json:"versionID,omitempty" jsonschema_description:"the ID of the pipeline version to run, the newest version when empty"`
+	Name            string                 `json:"name" jsonschema_description:"the name of the recurring run"`
+	Parameters      map[string]interface{} `json:"parameters,omitempty" jsonschema_description:"the input parameters of the pipeline"`
+	Cron            string                 `json:"cron,omitempty" jsonschema_description:"the cron expression of the schedule, f.e. 0 2 * * * for every night at 2:00"`
+	IntervalSeconds int64                  `json:"intervalSeconds,omitempty" jsonschema_description:"the interval between runs in seconds, used instead of cron"`
+	MaxConcurrency  int64                  `json:"maxConcurrency,omitempty" jsonschema_description:"the maximum number of runs running at the same time, 1 when empty"`
+}
+
+type ListRecurringRunsOutput struct {
+	RecurringRuns string `json:"recurringRuns" jsonschema_description:"the list of recurring runs with their schedule and status"`
+}
+
+type RecurringRunInput struct {
+	Namespace      string `json:"namespace" jsonschema_description:"the namespace of the pipeline server"`
+	APIURL         string `json:"apiURL,omitempty" jsonschema_description:"the URL of the pipelines API, read from the pipeline server when empty, a given URL is called without credentials"`
+	RecurringRunID string `json:"recurringRunID" jsonschema_description:"the ID of the recurring run"`
+}
+
+type ListQueuesInput struct {
+	Namespace string `json:"namespace,omitempty" jsonschema_description:"the namespace of the local queues, all namespaces when empty"`
+}
+
+type ListQueuesOutput struct {
+	Queues string `json:"queues" jsonschema_description:"the list of local and cluster queues with their quota usage"`
+}
+
+type ListWorkloadsOutput struct {
+	Workloads string `json:"workloads" jsonschema_description:"the list of workloads with their admission state"`
+}
+
+type ListRayClustersOutput struct {
+	RayClusters string `json:"rayClusters" jsonschema_description:"the list of ray clusters with their state, worker readiness and dashboard URL"`
+}
+
+type CreateRayClusterInput struct {
+	Namespace    string `json:"namespace" jsonschema_description:"the namespace to create the ray cluster in"`
+	Name         string `json:"name" jsonschema_description:"the name of the ray cluster"`
+	Image        string `json:"image,omitempty" jsonschema_description:"the ray image used by the head and the workers, the platform default when empty"`
+	LocalQueue   string `json:"localQueue,omitempty" jsonschema_description:"the Kueue local queue the cluster is submitted to, the cluster is not queued when empty"`
+	Workers      int    `json:"workers" jsonschema_description:"the number of worker replicas"`
+	WorkerCPU    string `json:"workerCPU,omitempty" jsonschema_description:"the CPU requested and limited for each worker, 1 when empty"`
+	WorkerMemory string `json:"workerMemory,omitempty" jsonschema_description:"the memory requested and limited for each worker, 4Gi when empty"`
+	WorkerGPUs   int    `json:"workerGPUs,omitempty" jsonschema_description:"the number of nvidia.com/gpu for each worker"`
+	HeadCPU      string `json:"headCPU,omitempty" jsonschema_description:"the CPU requested and limited for the head, 2 when empty"`
+	HeadMemory   string `json:"headMemory,omitempty" jsonschema_description:"the memory requested and limited for the head, 8Gi when empty"`
+}
+
+type ScaleRayClusterInput struct {
+	Namespace   string `json:"namespace" jsonschema_description:"the namespace of the ray cluster"`
+	Name        string `json:"name" jsonschema_description:"the name of the ray cluster"`
+	Workers     int    `json:"workers" jsonschema_description:"the new number of worker replicas"`
+	WorkerGroup string `json:"workerGroup,omitempty" jsonschema_description:"the worker group to scale, required only when the cluster has more than one"`
+}
+
+type RayClusterInput struct {
+	Namespace string `json:"namespace" jsonschema_description:"the namespace of the ray cluster"`
+	Name      string `json:"name" jsonschema_description:"the name of the ray cluster"`
+}
+
+type RayClusterOutput struct {
+	Message string `json:"message" jsonschema_description:"the message with result of ray cluster change"`
+}
+
+type CreateTrainingJobInput struct {
+	Namespace      string   `json:"namespace" jsonschema_description:"the namespace to create the training job in"`
+	Name           string   `json:"name" jsonschema_description:"the name of the training job"`
+	Image          string   `json:"image" jsonschema_description:"the image of the training containers"`
+	Command        []string `json:"command" jsonschema_description:"the command run in every replica - f.e. python, /mnt/storage/train.py"`
+	Workers        int      `json:"workers,omitempty" jsonschema_description:"the number of worker replicas next to the master"`
+	GPUs           int      `json:"gpus,omitempty" jsonschema_description:"the number of nvidia.com/gpu for each replica"`
+	CPU            string   `json:"cpu,omitempty" jsonschema_description:"the CPU requested and limited for each replica, 1 when empty"`
+	Memory         string   `json:"memory,omitempty" jsonschema_description:"the memory requested and limited for each replica, 4Gi when empty"`
+	StorageName    string   `json:"storageName,omitempty" jsonschema_description:"the name of the persistent volume claim mounted in every replica"`
+	MountPath      string   `json:"mountPath,omitempty" jsonschema_description:"where the storage is mounted, /mnt/storage when empty"`
+	ConnectionName string   `json:"connectionName,omitempty" jsonschema_description:"the name of the connection exposed to every replica as environment variables"`
+	LocalQueue     string   `json:"localQueue,omitempty" jsonschema_description:"the Kueue local queue the job is submitted to, the job is not queued when empty"`
+}
+
+type TrainingJobInput struct {
+	Namespace string `json:"namespace" jsonschema_description:"the namespace of the training job"`
+	Name      string `json:"name" jsonschema_description:"the name of the training job"`
+}
+
+type TrainingJobLogsInput struct {
+	Namespace string `json:"namespace" jsonschema_description:"the namespace of the training job"`
+	Name      string `json:"name" jsonschema_description:"the name of the training job"`
+	Rank      int    `json:"rank,omitempty" jsonschema_description:"the rank of the replica, 0 is the master and 1 the first worker"`
+	TailLines int64  `json:"tailLines,omitempty" jsonschema_description:"the number of lines from the end of the logs, 100 when empty"`
+}
+
+type TrainingJobOutput struct {
+	Message string `json:"message" jsonschema_description:"the message with the state or result of the training job change"`
+}
+
+type FindImagesInput struct {
+	Query string `json:"query" jsonschema_description:"comma separated packages or capabilities with optional versions - f.e. pytorch>=2.3, python 3.12, cuda"`
+	Limit int    `json:"limit,omitempty" jsonschema_description:"the maximum number of image tags returned, 5 when empty"`
+}
+
+type FindImagesOutput struct {
+	Images string `json:"images" jsonschema_description:"the image display name and tag pairs ranked by how well they match the query"`
+}
+
+type ImportNotebookImageInput struct {
+	ImageURL    string         `json:"imageURL" jsonschema_description:"the URL of the image - f.e. quay.io/org/notebook:1.0"`
+	DisplayName string         `json:"displayName" jsonschema_description:"the name of the image shown in the dashboard"`
+	Description string         `json:"description,omitempty" jsonschema_description:"the description of the image shown in the dashboard"`
+	Software    []ImagePackage `json:"software,omitempty" jsonschema_description:"the software installed in the image - f.e. Python v3.11, CUDA 12.1"`
+	Packages    []ImagePackage `json:"packages,omitempty" jsonschema_description:"the python packages installed in the image - f.e. PyTorch 2.4"`
+}
+
+type NotebookImageInput struct {
+	Name string `json:"name" jsonschema_description:"the display name or the image stream name of the custom image"`
+}
+
+type NotebookImageStatusInput struct {
+	Name    string `json:"name" jsonschema_description:"the display name or the image stream name of the custom image"`
+	Enabled bool   `json:"enabled" jsonschema_description:"whether the image is offered when creating workbenches"`
+}
+
+type NotebookImageOutput struct {
+	Message string `json:"message" jsonschema_description:"the message with result of notebook image change"`
+}
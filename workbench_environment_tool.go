@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+// env vars set by CreateWorkbench which the notebook server depends on
+var reservedEnvVars = map[string]bool{
+	"NOTEBOOK_ARGS": true,
+	"JUPYTER_IMAGE": true,
+}
+
+// Changes the connections and environment variables of an existing workbench
+func UpdateWorkbenchEnvironment(ctx context.Context, req *mcp.CallToolRequest, input UpdateWorkbenchEnvironmentInput) (*mcp.CallToolResult, WorkbenchOutput, error) {
+	newEnv, err := workbenchEnvVars(input.EnvVars, input.SecretEnvVars)
+	if err != nil {
+		return nil, WorkbenchOutput{}, err
+	}
+	for _, name := range input.RemoveEnvVars {
+		if reservedEnvVars[name] {
+			return nil, WorkbenchOutput{}, fmt.Errorf("environment variable %s is managed by the workbench and cannot be removed", name)
+		}
+	}
+
+	dyn, err := getDynamicClient()
+	if err != nil {
+		return nil, WorkbenchOutput{}, err
+	}
+	if err := checkConnectionsExist(ctx, dyn, input.Namespace, input.AddConnections); err != nil {
+		return nil, WorkbenchOutput{}, err
+	}
+
+	notebook, err := dyn.Resource(workbenchesGVR).Namespace(input.Namespace).Get(ctx, input.WorkbenchName, metav1.GetOptions{})
+	if err != nil {
+		return nil, WorkbenchOutput{}, fmt.Errorf("failed to get workbench %s: %v", input.WorkbenchName, err)
+	}
+
+	containers, _, _ := unstructured.NestedSlice(notebook.Object, "spec", "template", "spec", "containers")
+	container := workbenchContainer(containers, input.WorkbenchName)
+	if container == nil {
+		return nil, WorkbenchOutput{}, fmt.Errorf("workbench %s has no containers", input.WorkbenchName)
+	}
+
+	removeConnections := map[string]bool{}
+	for _, name := range input.RemoveConnections {
+		removeConnections[name] = true
+	}
+	envFrom, _, _ := unstructured.NestedSlice(container, "envFrom")
+	var keptEnvFrom []interface{}
+	attached := map[string]bool{}
+	for _, e := range envFrom {
+		entry, _ := e.(map[string]interface{})
+		secretName, _, _ := unstructured.NestedString(entry, "secretRef", "name")
+		if removeConnections[secretName] {
+			continue
+		}
+		attached[secretName] = true
+		keptEnvFrom = append(keptEnvFrom, e)
+	}
+	for _, name := range input.AddConnections {
+		if !attached[name] {
+			keptEnvFrom = append(keptEnvFrom, connectionEnvFrom(name))
+			attached[name] = true
+		}
+	}
+	container["envFrom"] = keptEnvFrom
+
+	replacedEnv := map[string]bool{}
+	for _, name := range input.RemoveEnvVars {
+		replacedEnv[name] = true
+	}
+	for _, e := range input.EnvVars {
+		replacedEnv[e.Name] = true
+	}
+	for _, e := range input.SecretEnvVars {
+		replacedEnv[e.Name] = true
+	}
+	env, _, _ := unstructured.NestedSlice(container, "env")
+	var keptEnv []interface{}
+	for _, e := range env {
+		entry, _ := e.(map[string]interface{})
+		if name, _ := entry["name"].(string); replacedEnv[name] {
+			continue
+		}
+		keptEnv = append(keptEnv, e)
+	}
+	container["env"] = append(keptEnv, newEnv...)
+
+	if err := unstructured.SetNestedSlice(notebook.Object, containers, "spec", "template", "spec", "containers"); err != nil {
+		return nil, WorkbenchOutput{}, fmt.Errorf("failed to set containers: %v", err)
+	}
+
+	var connections []string
+	recorded := map[string]bool{}
+	for _, ref := range strings.Split(notebook.GetAnnotations()["opendatahub.io/connections"], ",") {
+		name := ref[strings.LastIndex(ref, "/")+1:]
+		if name != "" && !removeConnections[name] && !recorded[name] {
+			connections = append(connections, name)
+			recorded[name] = true
+		}
+	}
+	for _, name := range input.AddConnections {
+		if !recorded[name] {
+			connections = append(connections, name)
+			recorded[name] = true
+		}
+	}
+	annotations := notebook.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	if len(connections) > 0 {
+		annotations["opendatahub.io/connections"] = connectionsAnnotation(input.Namespace, connections)
+	} else {
+		delete(annotations, "opendatahub.io/connections")
+	}
+	notebook.SetAnnotations(annotations)
+
+	_, err = dyn.Resource(workbenchesGVR).Namespace(input.Namespace).Update(ctx, notebook, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, WorkbenchOutput{}, fmt.Errorf("failed to update workbench %s: %v", input.WorkbenchName, err)
+	}
+
+	return nil, WorkbenchOutput{Message: fmt.Sprintf("Environment of workbench %s was updated", input.WorkbenchName)}, nil
+}
+
+// workbenchEnvVars builds container env entries from plain and secret backed variables
+func workbenchEnvVars(envVars []EnvVar, secretEnvVars []SecretEnvVar) ([]interface{}, error) {
+	var env []interface{}
+	for _, e := range envVars {
+		if reservedEnvVars[e.Name] {
+			return nil, fmt.Errorf("environment variable %s is managed by the workbench and cannot be set", e.Name)
+		}
+		env = append(env, map[string]interface{}{
+			"name":  e.Name,
+			"value": e.Value,
+		})
+	}
+	for _, e := range secretEnvVars {
+		if reservedEnvVars[e.Name] {
+			return nil, fmt.Errorf("environment variable %s is managed by the workbench and cannot be set", e.Name)
+		}
+		env = append(env, map[string]interface{}{
+			"name": e.Name,
+			"valueFrom": map[string]interface{}{
+				"secretKeyRef": map[string]interface{}{
+					"name": e.SecretName,
+					"key":  e.SecretKey,
+				},
+			},
+		})
+	}
+	return env, nil
+}
+
+func checkConnectionsExist(ctx context.Context, dyn dynamic.Interface, namespace string, connections []string) error {
+	for _, name := range connections {
+		if _, err := getConnection(ctx, dyn, namespace, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func connectionEnvFrom(name string) map[string]interface{} {
+	return map[string]interface{}{
+		"secretRef": map[string]interface{}{
+			"name": name,
+		},
+	}
+}
+
+// connectionsAnnotation formats connections the way the dashboard records them on notebooks
+func connectionsAnnotation(namespace string, connections []string) string {
+	refs := make([]string, 0, len(connections))
+	for _, name := range connections {
+		refs = append(refs, fmt.Sprintf("%s/%s", namespace, name))
+	}
+	return strings.Join(refs, ",")
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// workbenchHomeDir is where the home PVC of a workbench is mounted
+const workbenchHomeDir = "/opt/app-root/src"
+
+// execInWorkbench runs command in the notebook container of a running workbench and returns its stdout
+func execInWorkbench(ctx context.Context, namespace, workbench string, command []string) ([]byte, error) {
+	config, err := LogIntoClusterConfig()
+	if err != nil {
+		return nil, err
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to log into cluster: %v", err)
+	}
+
+	// the notebook controller runs every workbench as a single replica statefulset
+	request := clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(workbench+"-0").
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: workbench,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(config, "POST", request.URL())
+	if err != nil {
+		return nil, fmt.Errorf("failed to exec in workbench %s: %v", workbench, err)
+	}
+	var stdout, stderr bytes.Buffer
+	err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{Stdout: &stdout, Stderr: &stderr})
+	if err != nil {
+		return nil, fmt.Errorf("failed to run %s in workbench %s: %v %s", strings.Join(command, " "), workbench, err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// workbenchFilePath resolves p in the running workbench, following symlinks, and returns
+// the real path of the file when it is on the workbench storage
+func workbenchFilePath(ctx context.Context, nb unstructured.Unstructured, p string) (string, error) {
+	filePath, err := workbenchPath(nb, p)
+	if err != nil {
+		return "", err
+	}
+	// cat follows symlinks, so a link on the storage could point anywhere in the container
+	realPath, err := resolveWorkbenchPath(ctx, nb.GetNamespace(), nb.GetName(), filePath)
+	if err != nil {
+		return "", err
+	}
+	if _, err := workbenchPath(nb, realPath); err != nil {
+		return "", fmt.Errorf("path %s resolves to %s which is outside of the storage of workbench %s", p, realPath, nb.GetName())
+	}
+	return realPath, nil
+}
+
+// workbenchPath resolves a path relative to the workbench home directory and only allows
+// paths on the home directory or on storage mounted into the workbench, so files such as
+// mounted service account tokens cannot be read through it
+func workbenchPath(nb unstructured.Unstructured, p string) (string, error) {
+	resolved := path.Clean(p)
+	if !path.IsAbs(resolved) {
+		resolved = path.Join(workbenchHomeDir, resolved)
+	}
+	for _, dir := range append([]string{workbenchHomeDir}, workbenchStoragePaths(nb)...) {
+		dir = path.Clean(dir)
+		if dir != "/" && strings.HasPrefix(resolved, dir+"/") {
+			return resolved, nil
+		}
+	}
+	return "", fmt.Errorf("path %s is outside of the storage of workbench %s", p, nb.GetName())
+}
+
+// workbenchStoragePaths returns the mount paths of the PVCs mounted into the notebook container
+func workbenchStoragePaths(nb unstructured.Unstructured) []string {
+	claimVolumes := map[string]bool{}
+	volumes, _, _ := unstructured.NestedSlice(nb.Object, "spec", "template", "spec", "volumes")
+	for _, v := range volumes {
+		volume, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if claimName, _, _ := unstructured.NestedString(volume, "persistentVolumeClaim", "claimName"); claimName != "" {
+			name, _ := volume["name"].(string)
+			claimVolumes[name] = true
+		}
+	}
+
+	containers, _, _ := unstructured.NestedSlice(nb.Object, "spec", "template", "spec", "containers")
+	container := workbenchContainer(containers, nb.GetName())
+	volumeMounts, _, _ := unstructured.NestedSlice(container, "volumeMounts")
+	var paths []string
+	for _, m := range volumeMounts {
+		mount, ok := m.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := mount["name"].(string)
+		if mountPath, _ := mount["mountPath"].(string); claimVolumes[name] && mountPath != "" {
+			paths = append(paths, mountPath)
+		}
+	}
+	return paths
+}